@@ -0,0 +1,97 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// aliasTargetChanges adds the changes of the declarations that unchanged
+// aliases in the current API refer to, when those declarations are part of
+// the API as well, because the users of the alias are affected by them.
+func aliasTargetChanges(changes APIChanges, current, prev API, opts DiffOptions) APIChanges {
+	// A declaration may have several declaration changes, e.g. one for its
+	// fields and another for its methods, so all their changes are merged.
+	var declChanges = make(map[string][]Change)
+	for _, pkg := range changes {
+		for _, c := range pkg.Changes {
+			if d, ok := c.(DeclChange); ok {
+				key := pkg.Path + "." + d.Name
+				declChanges[key] = append(declChanges[key], d.Changes...)
+			}
+		}
+	}
+
+	prevPkgs := prevPackagesIndex(prev, opts)
+	var byPath = make(map[string]int, len(changes))
+	for i, pkg := range changes {
+		byPath[pkg.Path] = i
+	}
+
+	for _, pkg := range current {
+		prevPkg, ok := prevPkgs[pkg.Path]
+		if !ok {
+			continue
+		}
+
+		prevTypes := typesIndex(prevPkg.Types)
+		for _, t := range pkg.Types {
+			pt, ok := prevTypes[t.Name]
+			if !t.Alias || !ok || !pt.Alias || !typesEqual(pt.Type, t.Type, opts) {
+				continue
+			}
+
+			name, ok := namedPath(t.Type)
+			if !ok {
+				continue
+			}
+
+			cs, ok := declChanges[name]
+			if !ok {
+				continue
+			}
+
+			c := NewDeclChange(t.Name, TypeDefType, AliasTargetChanged{name, cs})
+			if i, ok := byPath[pkg.Path]; ok {
+				changes[i].Changes = append(changes[i].Changes, c)
+			} else {
+				byPath[pkg.Path] = len(changes)
+				changes = append(changes, NewPackageChanges(pkg.Name, pkg.Path, c))
+			}
+		}
+	}
+
+	return changes
+}
+
+// AliasSubstituted is reported in strict aliases mode when a type is
+// replaced by an identical one written with different aliases. See
+// DiffOptions.StrictAliases.
+type AliasSubstituted struct {
+	From     types.Type
+	To       types.Type
+	Severity Severity
+}
+
+func (a AliasSubstituted) String() string {
+	return fmt.Sprintf("type changed from %q to the identical %q", typeString(a.From), typeString(a.To))
+}
+
+// aliasSubstitution returns an AliasSubstituted change if strict aliases
+// are enabled and the given identical types are written with different
+// aliases.
+func aliasSubstitution(prev, current types.Type, opts DiffOptions) []Change {
+	if !opts.StrictAliases || (opts.StrictAliasSeverity == Cosmetic && !opts.ReportCosmetic) {
+		return nil
+	}
+
+	// Types read back from JSON only keep the key with the aliases
+	// replaced.
+	if isSerialized(prev) || isSerialized(current) {
+		return nil
+	}
+
+	if strictTypeKey(prev, opts.qualifier()) == strictTypeKey(current, nil) {
+		return nil
+	}
+	return []Change{AliasSubstituted{From: prev, To: current, Severity: opts.StrictAliasSeverity}}
+}
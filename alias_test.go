@@ -0,0 +1,103 @@
+package semverlint
+
+import "testing"
+
+func TestStrictAliases(t *testing.T) {
+	prev := `
+type B struct{}
+
+type Handler interface{ Handle(B) }
+
+func F(b B) {}
+
+var V B
+`
+	current := `
+type B struct{}
+
+type A = B
+
+type Handler interface{ Handle(A) }
+
+func F(b A) {}
+
+var V A
+`
+
+	testCases := []struct {
+		name  string
+		opts  DiffOptions
+		want  []string
+		major bool
+	}{
+		{
+			"default",
+			DiffOptions{},
+			[]string{`example.com/m: type definition A: was added`},
+			false,
+		},
+		{
+			"strict cosmetic without reporting cosmetic changes",
+			DiffOptions{StrictAliases: true},
+			[]string{`example.com/m: type definition A: was added`},
+			false,
+		},
+		{
+			"strict cosmetic",
+			DiffOptions{StrictAliases: true, ReportCosmetic: true},
+			[]string{
+				`example.com/m: function F: argument b with type example.com/m.A at position 0: type changed from "example.com/m.B" to the identical "example.com/m.A"`,
+				`example.com/m: interface Handler: method Handle: argument  with type example.com/m.A at position 0: type changed from "example.com/m.B" to the identical "example.com/m.A"`,
+				`example.com/m: package-level variable V: type changed from "example.com/m.B" to the identical "example.com/m.A"`,
+				`example.com/m: type definition A: was added`,
+			},
+			false,
+		},
+		{
+			"strict breaking",
+			DiffOptions{StrictAliases: true, StrictAliasSeverity: Breaking},
+			[]string{
+				`example.com/m: function F: argument b with type example.com/m.A at position 0: type changed from "example.com/m.B" to the identical "example.com/m.A"`,
+				`example.com/m: interface Handler: method Handle: argument  with type example.com/m.A at position 0: type changed from "example.com/m.B" to the identical "example.com/m.A"`,
+				`example.com/m: package-level variable V: type changed from "example.com/m.B" to the identical "example.com/m.A"`,
+				`example.com/m: type definition A: was added`,
+			},
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := diffSourcesWithOptions(t, prev, current, tc.opts)
+			AssertChanges(t, changes, tc.want)
+
+			if major := Recommend(changes) == MajorBump; major != tc.major {
+				t.Errorf("expected major bump to be %v, got %s", tc.major, Recommend(changes))
+			}
+		})
+	}
+}
+
+func TestStrictAliasesRetargeted(t *testing.T) {
+	// Retargeting an alias to another alias of the same type is only
+	// reported in strict mode.
+	prev := `
+type T struct{}
+
+type U = T
+
+type A = T
+`
+	current := `
+type T struct{}
+
+type U = T
+
+type A = U
+`
+
+	AssertChanges(t, diffSources(t, prev, current), nil)
+	AssertChanges(t, diffSourcesWithOptions(t, prev, current, DiffOptions{StrictAliases: true, StrictAliasSeverity: Additive}), []string{
+		`example.com/m: type definition A: type changed from "example.com/m.T" to the identical "example.com/m.U"`,
+	})
+}
@@ -0,0 +1,463 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/metrics"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// BenchmarkProjectAPI measures extracting the API of this repository, and
+// loading its packages with loadMode against type checking them from
+// source, which is what the choice of loadMode is based on.
+func BenchmarkProjectAPI(b *testing.B) {
+	b.Run("api", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := ProjectAPI("."); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	modes := []struct {
+		name string
+		mode packages.LoadMode
+	}{
+		{"export-data", loadMode},
+		{"source", loadMode | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps},
+	}
+
+	for _, m := range modes {
+		b.Run(m.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := packages.Load(&packages.Config{Mode: m.mode}, "./..."); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	r := newTestRepo(t)
+	first := r.commit(map[string]string{"m.go": packageSource(`func F() {}`)})
+	r.commit(map[string]string{"m.go": packageSource(`func G() {}`)})
+
+	for _, rev := range []string{first.String(), "HEAD~1"} {
+		v, err := ResolveVersion(r.dir, rev)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v.Commit != first {
+			t.Errorf("%s: expected commit %s, got %s", rev, first, v.Commit)
+		}
+
+		prev, err := VersionAPI(r.dir, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		current, err := ProjectAPI(r.dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		AssertChanges(t, Diff(current, prev), []string{
+			"example.com/m: function F: was removed",
+			"example.com/m: function G: was added",
+		})
+	}
+
+	if _, err := ResolveVersion(r.dir, "nope"); err == nil {
+		t.Error("expected an error resolving an unknown revision")
+	}
+}
+
+func TestSkipCgo(t *testing.T) {
+	dir := testModule(t, map[string]string{
+		"m.go":     packageSource(`func F() {}`),
+		"cgo/c.go": "package cgo\n\nimport \"C\"\n\nfunc G() {}\n",
+	})
+
+	var warnings []string
+	api, err := ProjectAPIWithOptions(dir, LoadOptions{
+		SkipCgo: true,
+		Warn: func(msg string) {
+			warnings = append(warnings, msg)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(api) != 1 || api[0].Path != testModulePath {
+		t.Errorf("expected only the package without cgo, got %v", api)
+	}
+
+	want := "skipping package at " + filepath.Join(dir, "cgo") + " because it uses cgo"
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("expected warning %q, got %q", want, warnings)
+	}
+}
+
+func TestSkipExcludedPackages(t *testing.T) {
+	dir := testModule(t, map[string]string{
+		"m.go":     packageSource(`func F() {}`),
+		"d/doc.go": "//go:build ignore\n\n// Package d does nothing.\npackage d\n",
+	})
+
+	var warnings []string
+	api, err := ProjectAPIWithOptions(dir, LoadOptions{
+		Warn: func(msg string) {
+			warnings = append(warnings, msg)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(api) != 1 || api[0].Path != testModulePath {
+		t.Errorf("expected only the package with Go files, got %v", api)
+	}
+
+	want := "skipping package example.com/m/d because its files are excluded by build constraints"
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("expected warning %q, got %q", want, warnings)
+	}
+}
+
+func TestSkipPackagesWithoutTypes(t *testing.T) {
+	dir := testModule(t, map[string]string{
+		"m.go":        packageSource(`func F() {}`),
+		"t/t_test.go": "package t\n\nimport \"testing\"\n\nfunc TestT(t *testing.T) {}\n",
+		"d/doc.go":    "//go:build ignore\n\n// Package d does nothing.\npackage d\n",
+	})
+
+	var warnings []string
+	api, err := ProjectAPIWithOptions(dir, LoadOptions{
+		Warn: func(msg string) {
+			warnings = append(warnings, msg)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, p := range api {
+		paths = append(paths, p.Path)
+	}
+
+	if want := []string{"example.com/m"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected packages %v, got %v", want, paths)
+	}
+
+	want := []string{"skipping package example.com/m/d because its files are excluded by build constraints"}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("expected warnings %q, got %q", want, warnings)
+	}
+
+	// The build system doesn't return these packages in every mode, so
+	// they are also checked directly.
+	warnings = nil
+	pkgs := usablePackages(dir, []*packages.Package{
+		{PkgPath: "example.com/m/t"},
+		{PkgPath: "example.com/m/d", IgnoredFiles: []string{filepath.Join(dir, "d", "doc.go")}},
+		{PkgPath: "example.com/m/n", GoFiles: []string{filepath.Join(dir, "n", "n.go")}},
+		{
+			PkgPath: "example.com/m",
+			GoFiles: []string{filepath.Join(dir, "m.go")},
+			Types:   types.NewPackage("example.com/m", "m"),
+		},
+	}, LoadOptions{
+		Warn: func(msg string) {
+			warnings = append(warnings, msg)
+		},
+	})
+
+	if len(pkgs) != 1 || pkgs[0].PkgPath != "example.com/m" {
+		t.Errorf("expected only the package with types, got %v", pkgs)
+	}
+
+	want = []string{
+		"skipping package example.com/m/t because it has no Go files other than tests",
+		"skipping package example.com/m/d because its files are excluded by build constraints",
+		"skipping package example.com/m/n because it has no type information",
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("expected warnings %q, got %q", want, warnings)
+	}
+}
+
+func TestSkipDirs(t *testing.T) {
+	dir := testModule(t, map[string]string{
+		"m.go":                      packageSource(`func F() {}`),
+		"testdata/t/t.go":           "package t\n\nfunc T() {}\n",
+		"_examples/e/e.go":          "package e\n\nfunc E() {}\n",
+		"pkg/testdata/u/u.go":       "package u\n\nfunc U() {}\n",
+		"pkg/testdata.go":           "package pkg\n\nfunc P() {}\n",
+		"vendor/example.com/v/v.go": "package v\n\nfunc V() {}\n",
+	})
+
+	testCases := []struct {
+		name string
+		skip []string
+		want []string
+	}{
+		{
+			"default",
+			nil,
+			[]string{"example.com/m", "example.com/m/pkg", "example.com/m/pkg/testdata/u", "example.com/m/testdata/t"},
+		},
+		{
+			"custom",
+			[]string{"testdata"},
+			[]string{"example.com/m", "example.com/m/_examples/e", "example.com/m/pkg"},
+		},
+		{
+			"none",
+			[]string{},
+			[]string{"example.com/m", "example.com/m/_examples/e", "example.com/m/pkg", "example.com/m/pkg/testdata/u", "example.com/m/testdata/t"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			api, err := ProjectAPIWithOptions(dir, LoadOptions{SkipDirs: tc.skip})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var paths []string
+			for _, p := range api {
+				paths = append(paths, p.Path)
+			}
+
+			if !reflect.DeepEqual(paths, tc.want) {
+				t.Errorf("expected packages %v, got %v", tc.want, paths)
+			}
+		})
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := testModule(t, map[string]string{
+		"a.go":      packageSource(`func A() {}`),
+		"b.go":      packageSource(`func B() {}`),
+		"b_test.go": packageSource(`func C() {}`),
+		"sub/s.go":  "package sub\n\nfunc S() {}\n",
+	})
+
+	api, err := ProjectAPIWithOptions(dir, LoadOptions{Files: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got = make(map[string][]string)
+	for _, p := range api {
+		got[p.Path] = p.Files
+	}
+
+	want := map[string][]string{
+		"example.com/m":     {"a.go", "b.go"},
+		"example.com/m/sub": {"sub/s.go"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected files %v, got %v", want, got)
+	}
+}
+
+func TestBestEffort(t *testing.T) {
+	dir := testModule(t, map[string]string{
+		"good/g.go":   "package good\n\nfunc G() {}\n",
+		"broken/b.go": "package broken\n\nfunc B() Missing { return nil }\n\nfunc C() {}\n",
+	})
+
+	api, err := ProjectAPI(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, p := range api {
+		paths = append(paths, p.Path)
+	}
+
+	if want := []string{"example.com/m/broken", "example.com/m/good"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected packages %v by default, got %v", want, paths)
+	}
+
+	var warnings []string
+	api, err = ProjectAPIWithOptions(dir, LoadOptions{
+		BestEffort: true,
+		Warn: func(msg string) {
+			warnings = append(warnings, msg)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(api) != 1 || api[0].Path != "example.com/m/good" {
+		t.Errorf("expected only the good package, got %v", api)
+	}
+
+	want := "skipping package example.com/m/broken because it failed to load"
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], want) {
+		t.Errorf("expected a warning starting with %q, got %q", want, warnings)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	r := newTestRepo(t)
+	first := r.commit(map[string]string{"m.go": packageSource(`func F() {}`)})
+	second := r.commit(map[string]string{"m.go": packageSource(`func G() {}`)})
+	head := r.commit(map[string]string{"m.go": packageSource(`func H() {}`)})
+	r.tag("v1.2.0", second)
+	r.tag("v1.0.0", first)
+	r.tag("HEAD", first)
+	r.tag("not-a-version", second)
+
+	versions, err := Versions(r.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Version{{"HEAD", head}, {"v1.0.0", first}, {"v1.2.0", second}}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("expected versions %v, got %v", want, versions)
+	}
+
+	latest, ok := LatestTag(versions)
+	if !ok || latest != want[2] {
+		t.Errorf("expected latest tag %v, got %v", want[2], latest)
+	}
+
+	if _, ok := LatestTag([]Version{{"HEAD", head}}); ok {
+		t.Error("expected no latest tag without released versions")
+	}
+}
+
+func TestByVersion(t *testing.T) {
+	versions := []Version{{Name: "v2.0.0"}, {Name: "HEAD"}, {Name: "weird"}, {Name: "v1.0.0"}, {Name: "HEAD"}}
+	sort.Stable(byVersion(versions))
+
+	var names = make([]string, len(versions))
+	for i, v := range versions {
+		names[i] = v.Name
+	}
+
+	want := []string{"HEAD", "HEAD", "weird", "v1.0.0", "v2.0.0"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected order %v, got %v", want, names)
+	}
+}
+
+func TestBatchSize(t *testing.T) {
+	dir := testModule(t, map[string]string{
+		"m.go":   packageSource(`import "example.com/m/a"` + "\n\nfunc F(a.A) {}"),
+		"a/a.go": "package a\n\ntype A struct{ X int }\n",
+		"b/b.go": "package b\n\nimport \"example.com/m/a\"\n\nfunc B() a.A { return a.A{} }\n",
+		"c/c.go": "package c\n\nconst C = 1\n",
+		"d/d.go": "package d\n\nfunc D() { undefined() }\n\nfunc E(\n",
+	})
+
+	paths := func(api API) []string {
+		var result []string
+		for _, p := range api {
+			result = append(result, p.Path)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	for _, bestEffort := range []bool{false, true} {
+		single, err := ProjectAPIWithOptions(dir, LoadOptions{BestEffort: bestEffort})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, size := range []int{1, 2, 3, 4} {
+			batched, err := ProjectAPIWithOptions(dir, LoadOptions{BatchSize: size, BestEffort: bestEffort})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := paths(batched), paths(single); !reflect.DeepEqual(got, want) {
+				t.Errorf("batch size %d, best effort %v: expected packages %v, got %v", size, bestEffort, want, got)
+			}
+
+			changes := DiffWithOptions(batched, single, DiffOptions{ReportCosmetic: true})
+			if s := changes.Summarize(); s != (Summary{}) {
+				t.Errorf("batch size %d, best effort %v: expected the same API as a single load, got changes:\n%s", size, bestEffort, changes.UnifiedText())
+			}
+		}
+	}
+}
+
+// BenchmarkBatchSize compares the peak heap used while extracting the API of
+// this repository in a single load and in batches.
+func BenchmarkBatchSize(b *testing.B) {
+	for _, size := range []int{0, 1, 4} {
+		b.Run(fmt.Sprintf("batch-%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+
+			var peak uint64
+			for b.Loop() {
+				p := peakHeap(func() {
+					if _, err := ProjectAPIWithOptions(".", LoadOptions{BatchSize: size}); err != nil {
+						b.Fatal(err)
+					}
+				})
+
+				if p > peak {
+					peak = p
+				}
+			}
+			b.ReportMetric(float64(peak), "peak-heap-B")
+		})
+	}
+}
+
+// peakHeap returns the peak size of the heap objects while running fn,
+// sampled every millisecond.
+func peakHeap(fn func()) uint64 {
+	runtime.GC()
+
+	var peak uint64
+	var sample = []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			metrics.Read(sample)
+			if v := sample[0].Value.Uint64(); v > peak {
+				peak = v
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	fn()
+	close(done)
+	<-stopped
+	return peak
+}
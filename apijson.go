@@ -0,0 +1,395 @@
+package semverlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io"
+)
+
+// apiFormatVersion is the version of the format written by WriteAPI.
+const apiFormatVersion = 5
+
+type apiFile struct {
+	Version  int       `json:"version"`
+	Packages []Package `json:"packages"`
+}
+
+// WriteAPI writes the given API as JSON, so it can be diffed later, maybe in
+// a different machine, after reading it back with ReadAPI.
+func WriteAPI(w io.Writer, api API) error {
+	return json.NewEncoder(w).Encode(apiFile{apiFormatVersion, api})
+}
+
+// ReadAPI reads an API written by WriteAPI.
+//
+// Types are read back as opaque types that can only be compared with other
+// types by their string representation, which is enough to diff them, but
+// ModulePaths in DiffOptions don't apply to them and some changes that
+// require inspecting the types, such as the constraints of type parameters
+// being narrowed or widened, are reported as plain type changes.
+func ReadAPI(r io.Reader) (API, error) {
+	var f apiFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("unable to decode API: %s", err)
+	}
+
+	if f.Version != apiFormatVersion {
+		return nil, fmt.Errorf("unsupported API format version %d", f.Version)
+	}
+
+	if err := checkTypes(f.Packages); err != nil {
+		return nil, fmt.Errorf("invalid API: %s", err)
+	}
+
+	return API(f.Packages), nil
+}
+
+// checkTypes returns an error if any declaration of the given packages is
+// missing its type, which can only happen if the JSON was not written by
+// WriteAPI, since diffing them requires all of them.
+func checkTypes(pkgs []Package) error {
+	missing := func(pkg Package, name string) error {
+		return fmt.Errorf("missing type of %s in package %s", name, pkg.Path)
+	}
+
+	checkTypeParams := func(pkg Package, name string, tparams []TypeParam) error {
+		for _, tp := range tparams {
+			if tp.Constraint == nil {
+				return missing(pkg, name+" type parameter "+tp.Name)
+			}
+		}
+		return nil
+	}
+
+	checkFunc := func(pkg Package, name string, f Func) error {
+		if err := checkTypeParams(pkg, name, f.TypeParams); err != nil {
+			return err
+		}
+
+		for i, p := range f.Args {
+			if p.Type == nil {
+				return missing(pkg, fmt.Sprintf("%s argument %d", name, i))
+			}
+		}
+
+		for i, p := range f.Return {
+			if p.Type == nil {
+				return missing(pkg, fmt.Sprintf("%s result %d", name, i))
+			}
+		}
+		return nil
+	}
+
+	for _, pkg := range pkgs {
+		for _, v := range pkg.Vars {
+			if v.Type == nil {
+				return missing(pkg, v.Name)
+			}
+		}
+
+		for _, c := range pkg.Consts {
+			if c.Type == nil {
+				return missing(pkg, c.Name)
+			}
+		}
+
+		for _, t := range pkg.Types {
+			if t.Type == nil {
+				return missing(pkg, t.Name)
+			}
+
+			if err := checkTypeParams(pkg, t.Name, t.TypeParams); err != nil {
+				return err
+			}
+
+			for _, m := range t.Methods {
+				if err := checkFunc(pkg, t.Name+"."+m.Name, m); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, f := range pkg.Funcs {
+			if err := checkFunc(pkg, f.Name, f); err != nil {
+				return err
+			}
+		}
+
+		for _, s := range pkg.Structs {
+			if err := checkTypeParams(pkg, s.Name, s.TypeParams); err != nil {
+				return err
+			}
+
+			for _, f := range s.Fields {
+				if f.Type == nil {
+					return missing(pkg, s.Name+"."+f.Name)
+				}
+			}
+
+			for _, m := range s.Methods {
+				if err := checkFunc(pkg, s.Name+"."+m.Name, m); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, i := range pkg.Interfaces {
+			if err := checkTypeParams(pkg, i.Name, i.TypeParams); err != nil {
+				return err
+			}
+
+			for _, t := range i.TypeSet {
+				if t == nil {
+					return missing(pkg, i.Name+" type set element")
+				}
+			}
+
+			for _, m := range i.Methods {
+				if err := checkFunc(pkg, i.Name+"."+m.Name, m); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// serializedType is a type read back from JSON. It keeps the string of the
+// type for display and its key, as returned by typeKey or typeSetElemKey for
+// unions, for comparisons, along with the few details about the type needed
+// to diff it.
+type serializedType struct {
+	repr string
+	key  string
+	// named is the name of the type as returned by namedPath, if it's a
+	// named type.
+	named string
+	// unexported are the unexported types it refers to, as returned by
+	// unexportedRefs.
+	unexported []string
+}
+
+func (t *serializedType) Underlying() types.Type { return t }
+func (t *serializedType) String() string         { return t.repr }
+
+// isSerialized reports whether the type is a type read back from JSON or a
+// pointer to one.
+func isSerialized(t types.Type) bool {
+	switch t := t.(type) {
+	case *serializedType:
+		return true
+	case *types.Pointer:
+		return isSerialized(t.Elem())
+	}
+	return false
+}
+
+// typeJSON is the JSON representation of a type. Pointers are kept as such
+// so changes between values and pointers can still be detected.
+type typeJSON struct {
+	Type       string    `json:"type,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	Named      string    `json:"named,omitempty"`
+	Unexported []string  `json:"unexported,omitempty"`
+	Pointer    *typeJSON `json:"pointer,omitempty"`
+}
+
+func newTypeJSON(t types.Type) *typeJSON {
+	switch t := t.(type) {
+	case nil:
+		return nil
+	case *serializedType:
+		return &typeJSON{Type: t.repr, Key: t.key, Named: t.named, Unexported: t.unexported}
+	case *types.Pointer:
+		return &typeJSON{Pointer: newTypeJSON(t.Elem())}
+	default:
+		named, _ := namedPath(t)
+		return &typeJSON{
+			Type:       typeString(t),
+			Key:        typeSetElemKey(t, nil),
+			Named:      named,
+			Unexported: unexportedRefs(t),
+		}
+	}
+}
+
+// typ returns the type, which is nil if there's no type, as json leaves nil
+// pointers for null values.
+func (t *typeJSON) typ() types.Type {
+	switch {
+	case t == nil:
+		return nil
+	case t.Pointer != nil:
+		return types.NewPointer(t.Pointer.typ())
+	default:
+		return &serializedType{
+			repr:       t.Type,
+			key:        t.Key,
+			named:      t.Named,
+			unexported: t.Unexported,
+		}
+	}
+}
+
+func (p Param) MarshalJSON() ([]byte, error) {
+	type param Param
+	return json.Marshal(struct {
+		param
+		Type *typeJSON
+	}{param(p), newTypeJSON(p.Type)})
+}
+
+func (p *Param) UnmarshalJSON(b []byte) error {
+	type param Param
+	var v struct {
+		*param
+		Type *typeJSON
+	}
+	v.param = (*param)(p)
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	p.Type = v.Type.typ()
+	return nil
+}
+
+func (p TypeParam) MarshalJSON() ([]byte, error) {
+	type typeParam TypeParam
+	return json.Marshal(struct {
+		typeParam
+		Constraint *typeJSON
+	}{typeParam(p), newTypeJSON(p.Constraint)})
+}
+
+func (p *TypeParam) UnmarshalJSON(b []byte) error {
+	type typeParam TypeParam
+	var v struct {
+		*typeParam
+		Constraint *typeJSON
+	}
+	v.typeParam = (*typeParam)(p)
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	p.Constraint = v.Constraint.typ()
+	return nil
+}
+
+func (f Field) MarshalJSON() ([]byte, error) {
+	type field Field
+	return json.Marshal(struct {
+		field
+		Type *typeJSON
+	}{field(f), newTypeJSON(f.Type)})
+}
+
+func (f *Field) UnmarshalJSON(b []byte) error {
+	type field Field
+	var v struct {
+		*field
+		Type *typeJSON
+	}
+	v.field = (*field)(f)
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	f.Type = v.Type.typ()
+	return nil
+}
+
+func (d Var) MarshalJSON() ([]byte, error) {
+	type variable Var
+	return json.Marshal(struct {
+		variable
+		Type *typeJSON
+	}{variable(d), newTypeJSON(d.Type)})
+}
+
+func (d *Var) UnmarshalJSON(b []byte) error {
+	type variable Var
+	var v struct {
+		*variable
+		Type *typeJSON
+	}
+	v.variable = (*variable)(d)
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	d.Type = v.Type.typ()
+	return nil
+}
+
+func (d Const) MarshalJSON() ([]byte, error) {
+	type constant Const
+	return json.Marshal(struct {
+		constant
+		Type *typeJSON
+	}{constant(d), newTypeJSON(d.Type)})
+}
+
+func (d *Const) UnmarshalJSON(b []byte) error {
+	type constant Const
+	var v struct {
+		*constant
+		Type *typeJSON
+	}
+	v.constant = (*constant)(d)
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	d.Type = v.Type.typ()
+	return nil
+}
+
+func (d TypeDef) MarshalJSON() ([]byte, error) {
+	type typeDef TypeDef
+	return json.Marshal(struct {
+		typeDef
+		Type *typeJSON
+	}{typeDef(d), newTypeJSON(d.Type)})
+}
+
+func (d *TypeDef) UnmarshalJSON(b []byte) error {
+	type typeDef TypeDef
+	var v struct {
+		*typeDef
+		Type *typeJSON
+	}
+	v.typeDef = (*typeDef)(d)
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	d.Type = v.Type.typ()
+	return nil
+}
+
+func (i Interface) MarshalJSON() ([]byte, error) {
+	type iface Interface
+	var typeSet = make([]*typeJSON, len(i.TypeSet))
+	for j, t := range i.TypeSet {
+		typeSet[j] = newTypeJSON(t)
+	}
+	return json.Marshal(struct {
+		iface
+		TypeSet []*typeJSON
+	}{iface(i), typeSet})
+}
+
+func (i *Interface) UnmarshalJSON(b []byte) error {
+	type iface Interface
+	var v struct {
+		*iface
+		TypeSet []*typeJSON
+	}
+	v.iface = (*iface)(i)
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	i.TypeSet = nil
+	for _, t := range v.TypeSet {
+		i.TypeSet = append(i.TypeSet, t.typ())
+	}
+	return nil
+}
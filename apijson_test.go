@@ -0,0 +1,124 @@
+package semverlint
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAPI(t *testing.T) {
+	prev := sourceAPI(t, `
+type Point struct {
+	X, Y int
+}
+
+type Shape interface {
+	Area() float64
+}
+
+type List[T any] []T
+
+const Max = 10
+
+var Default Point
+
+func New(x, y int) Point { return Point{x, y} }
+func (p Point) Dist(q Point) float64 { return 0 }
+func Scale(p Point) {}
+`)
+	current := sourceAPI(t, `
+type Point struct {
+	X, Y int64
+}
+
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+type List[T any] []T
+
+type Pair[K comparable, V any] map[K]V
+
+const Max = 11
+
+var Default *Point
+
+func New(x, y int64) (Point, error) { return Point{x, y}, nil }
+func (p Point) Dist(q Point) float64 { return 0 }
+func (p Point) Add(q Point) Point { return p }
+func Scale(p *Point) {}
+`)
+
+	roundTrip := func(api API) API {
+		var b bytes.Buffer
+		if err := WriteAPI(&b, api); err != nil {
+			t.Fatal(err)
+		}
+
+		read, err := ReadAPI(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return read
+	}
+
+	want := Diff(current, prev).Strings()
+	got := Diff(roundTrip(current), roundTrip(prev)).Strings()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the same changes as the extracted APIs:\n%s\ngot:\n%s",
+			strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	if changes := Diff(roundTrip(current), current).Strings(); len(changes) > 0 {
+		t.Errorf("expected no changes between an API and its copy read back, got %v", changes)
+	}
+}
+
+func TestReadAPIErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"invalid", `{"version":`, "unable to decode API"},
+		{"version", `{"version":0,"packages":[]}`, "unsupported API format version 0"},
+		{
+			"missing type",
+			fmt.Sprintf(`{"version":%d,"packages":[{"name":"m","path":"example.com/m","vars":[{"name":"V"}]}]}`, apiFormatVersion),
+			"invalid API: missing type of",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadAPI(strings.NewReader(tc.input))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestReadAPIConstraints(t *testing.T) {
+	prev := sourceAPI(t, "func F[T any](T) {}")
+	current := sourceAPI(t, "func F[T comparable](T) {}")
+
+	var b bytes.Buffer
+	if err := WriteAPI(&b, prev); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadAPI(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Constraints can't be compared once serialized, so narrowing them is
+	// reported as a plain type change.
+	AssertChanges(t, Diff(current, read), []string{
+		`example.com/m: function F: type parameter T at position 0: type changed from "any" to "comparable"`,
+	})
+}
@@ -0,0 +1,172 @@
+package semverlint
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectAPIFromArchive returns the public API of the project in the given
+// archive, whose format is either "zip" or "tar.gz". The archive can contain
+// the project at its root or inside a single top-level directory, as in the
+// archives of repositories and module proxies.
+func ProjectAPIFromArchive(r io.Reader, format string) (API, error) {
+	dir, err := os.MkdirTemp("", "semverlint")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	switch format {
+	case "zip":
+		err = extractZip(r, dir)
+	case "tar.gz":
+		err = extractTarGz(r, dir)
+	default:
+		return nil, fmt.Errorf("unsupported archive format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract archive: %s", err)
+	}
+
+	root, err := archiveRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return ProjectAPI(root)
+}
+
+// archiveRoot returns the directory of the project extracted in the given
+// directory, which is the only directory in it if there's no go.mod file.
+func archiveRoot(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		return dir, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("unable to read extracted archive: %s", err)
+	}
+
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
+
+// extractPath returns the path where the file with the given name in an
+// archive is extracted inside dir. Names that would be extracted outside
+// dir are rejected.
+func extractPath(dir, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("invalid absolute path in archive: %s", name)
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if path != dir && !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid path outside of the archive: %s", name)
+	}
+	return path, nil
+}
+
+func extractFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func extractZip(r io.Reader, dir string) error {
+	// Zip files need to be read at random positions.
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		path, err := extractPath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+
+		// Only directories and regular files are extracted, so symlinks
+		// can't point outside of the directory.
+		switch mode := f.Mode(); {
+		case mode.IsDir():
+			err = os.MkdirAll(path, 0755)
+		case mode.IsRegular():
+			err = extractZipFile(f, path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipFile(f *zip.File, path string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return extractFile(path, rc)
+}
+
+func extractTarGz(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err := extractPath(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		// Only directories and regular files are extracted, so symlinks
+		// can't point outside of the directory.
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, 0755)
+		case tar.TypeReg:
+			err = extractFile(path, tr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
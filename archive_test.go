@@ -0,0 +1,138 @@
+package semverlint
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// archiveFiles are the files of the module in the archives of the tests,
+// inside a top-level directory as in the archives of module proxies.
+var archiveFiles = map[string]string{
+	"m@v1.0.0/go.mod":   "module example.com/m\n\ngo 1.26\n",
+	"m@v1.0.0/m.go":     packageSource("func F() {}"),
+	"m@v1.0.0/sub/s.go": "package sub\n\nconst S = 1\n",
+}
+
+// sortedNames returns the names of the given files sorted, so archives are
+// always written in the same order.
+func sortedNames(files map[string]string) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for _, name := range sortedNames(files) {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := w.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func tarGzArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gz)
+	for _, name := range sortedNames(files) {
+		hdr := &tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestProjectAPIFromArchive(t *testing.T) {
+	want := moduleAPI(t, map[string]string{
+		"m.go":     packageSource("func F() {}"),
+		"sub/s.go": "package sub\n\nconst S = 1\n",
+	})
+
+	archives := map[string][]byte{
+		"zip":    zipArchive(t, archiveFiles),
+		"tar.gz": tarGzArchive(t, archiveFiles),
+	}
+
+	for format, data := range archives {
+		t.Run(format, func(t *testing.T) {
+			api, err := ProjectAPIFromArchive(bytes.NewReader(data), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			AssertChanges(t, Diff(api, want), nil)
+			if len(api) != 2 {
+				t.Errorf("expected 2 packages, got %d", len(api))
+			}
+		})
+	}
+}
+
+func TestProjectAPIFromArchiveErrors(t *testing.T) {
+	traversal := map[string]string{
+		"go.mod":        "module example.com/m\n\ngo 1.26\n",
+		"../outside.go": "package outside\n",
+	}
+
+	testCases := []struct {
+		name   string
+		data   []byte
+		format string
+		err    string
+	}{
+		{"format", zipArchive(t, archiveFiles), "rar", `unsupported archive format "rar"`},
+		{"zip traversal", zipArchive(t, traversal), "zip", "invalid path outside of the archive: ../outside.go"},
+		{"tar.gz traversal", tarGzArchive(t, traversal), "tar.gz", "invalid path outside of the archive: ../outside.go"},
+		{"absolute", tarGzArchive(t, map[string]string{"/etc/m.go": "package m\n"}), "tar.gz", "invalid absolute path in archive: /etc/m.go"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ProjectAPIFromArchive(bytes.NewReader(tc.data), tc.format)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package semverlint
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Strings returns the changes rendered as strings, one per top-level change,
+// with the form "<package path>: <change>", sorted so they don't depend on
+// the order of the packages and changes.
+func (c APIChanges) Strings() []string {
+	var result []string
+	c.Walk(func(pkg PackageChanges, change Change) bool {
+		result = append(result, pkg.Path+": "+change.String())
+		return false
+	})
+	sort.Strings(result)
+	return result
+}
+
+// AssertChanges fails the given test if the changes rendered by Strings are
+// not exactly the wanted ones, in any order. It's meant to be used in the
+// tests of projects that want to pin the expected evolution of their API.
+func AssertChanges(t testing.TB, got APIChanges, want []string) {
+	t.Helper()
+
+	var expected = make([]string, len(want))
+	copy(expected, want)
+	sort.Strings(expected)
+
+	actual := got.Strings()
+	missing, unexpected := stringsDiff(expected, actual)
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("unexpected API changes")
+	for _, s := range missing {
+		b.WriteString("\n- " + s)
+	}
+	for _, s := range unexpected {
+		b.WriteString("\n+ " + s)
+	}
+	t.Error(b.String())
+}
+
+// stringsDiff returns the elements of the sorted slice a missing in the
+// sorted slice b and the ones of b missing in a.
+func stringsDiff(a, b []string) (missing, unexpected []string) {
+	var i, j int
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case a[i] < b[j]:
+			missing = append(missing, a[i])
+			i++
+		default:
+			unexpected = append(unexpected, b[j])
+			j++
+		}
+	}
+	missing = append(missing, a[i:]...)
+	unexpected = append(unexpected, b[j:]...)
+	return missing, unexpected
+}
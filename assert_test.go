@@ -0,0 +1,71 @@
+package semverlint
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recordingTB is a testing.TB that records the errors reported instead of
+// failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Error(args ...interface{}) {
+	for _, a := range args {
+		r.errors = append(r.errors, a.(string))
+	}
+}
+
+func TestAssertChanges(t *testing.T) {
+	changes := diffSources(t, `
+func Foo(a int) {}
+func Bar() {}
+`, `
+func Foo(a string) {}
+func Baz() {}
+`)
+
+	want := []string{
+		`example.com/m: function Foo: argument a with type string at position 0: type changed from "int" to "string"`,
+		`example.com/m: function Baz: was added`,
+		`example.com/m: function Bar: was removed`,
+	}
+
+	t.Run("match", func(t *testing.T) {
+		var tb recordingTB
+		AssertChanges(&tb, changes, want)
+		if len(tb.errors) > 0 {
+			t.Errorf("expected no errors, got %v", tb.errors)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		var tb recordingTB
+		AssertChanges(&tb, changes, []string{
+			want[0],
+			want[1],
+			`example.com/m: function Qux: was removed`,
+		})
+
+		expected := []string{"unexpected API changes" +
+			"\n- example.com/m: function Qux: was removed" +
+			"\n+ example.com/m: function Bar: was removed"}
+		if !reflect.DeepEqual(tb.errors, expected) {
+			t.Errorf("expected errors %q, got %q", expected, tb.errors)
+		}
+	})
+}
+
+func TestStringsDiff(t *testing.T) {
+	missing, unexpected := stringsDiff([]string{"a", "c", "d"}, []string{"b", "c", "e"})
+	if want := []string{"a", "d"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("expected missing %v, got %v", want, missing)
+	}
+	if want := []string{"b", "e"}; !reflect.DeepEqual(unexpected, want) {
+		t.Errorf("expected unexpected %v, got %v", want, unexpected)
+	}
+}
@@ -0,0 +1,84 @@
+package semverlint
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// cacheVersion is part of the keys of the cached changes, so they are
+// invalidated when it's bumped. It must be bumped whenever the extraction
+// of the APIs or the diff change.
+const cacheVersion = 11
+
+// DiffCache caches on disk the changes between pairs of commits, which
+// never change as long as the commits don't.
+type DiffCache struct {
+	// Dir is the directory where the changes are stored.
+	Dir string
+
+	// Warn, if not nil, is called with the errors storing changes in the
+	// cache, which don't make the diffs fail.
+	Warn func(msg string)
+}
+
+func (c DiffCache) warn(format string, args ...interface{}) {
+	if c.Warn != nil {
+		c.Warn(fmt.Sprintf(format, args...))
+	}
+}
+
+func (c DiffCache) path(prev, current plumbing.Hash) string {
+	return filepath.Join(c.Dir, fmt.Sprintf("v%d-%s-%s.json", cacheVersion, prev, current))
+}
+
+// get returns the cached changes between the given commits. Changes that
+// can't be read are treated as not cached.
+func (c DiffCache) get(prev, current plumbing.Hash) (APIChanges, bool) {
+	data, err := os.ReadFile(c.path(prev, current))
+	if err != nil {
+		return nil, false
+	}
+
+	changes, err := ReadChanges(bytes.NewReader(data))
+	if err != nil {
+		return nil, false
+	}
+	return changes, true
+}
+
+func (c DiffCache) put(prev, current plumbing.Hash, changes APIChanges) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return fmt.Errorf("unable to create cache directory: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteChanges(&buf, changes); err != nil {
+		return fmt.Errorf("unable to encode changes: %s", err)
+	}
+
+	// Write to a temporary file first, so concurrent readers never see a
+	// partially written file.
+	tmp, err := os.CreateTemp(c.Dir, "tmp-")
+	if err != nil {
+		return fmt.Errorf("unable to write cache: %s", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("unable to write cache: %s", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write cache: %s", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(prev, current)); err != nil {
+		return fmt.Errorf("unable to write cache: %s", err)
+	}
+	return nil
+}
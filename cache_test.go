@@ -0,0 +1,111 @@
+package semverlint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffRefsCached(t *testing.T) {
+	r := newTestRepo(t)
+	base := r.commit(map[string]string{"m.go": packageSource(`func F() {}`)})
+	head := r.commit(map[string]string{"m.go": packageSource(`func F(n int) {}`)})
+	r.tag("v1.0.0", base)
+	r.tag("v1.1.0", head)
+
+	cache := DiffCache{Dir: filepath.Join(t.TempDir(), "cache")}
+	changes, err := DiffRefsCached(r.dir, "v1.0.0", "v1.1.0", cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"example.com/m: function F: argument n with type int at position 0: was added"}
+	AssertChanges(t, changes, want)
+
+	path := filepath.Join(cache.Dir, fmt.Sprintf("v%d-%s-%s.json", cacheVersion, base, head))
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected the changes to be cached: %s", err)
+	}
+
+	// Replace the cached changes, so they are only returned if they're read
+	// from the cache.
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cached := diffSources(t, "func F() {}", "func G() {}")
+	if err := WriteChanges(f, cached); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = DiffRefsCached(r.dir, "v1.0.0", "v1.1.0", cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AssertChanges(t, changes, cached.Strings())
+
+	// Changes that can't be read are computed again.
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = DiffRefsCached(r.dir, "v1.0.0", "v1.1.0", cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AssertChanges(t, changes, want)
+
+	entries, err := os.ReadDir(cache.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected only the cached changes in the cache directory, got %d files", len(entries))
+	}
+}
+
+func TestDiffRefsCachedUnwritable(t *testing.T) {
+	r := newTestRepo(t)
+	base := r.commit(map[string]string{"m.go": packageSource(`func F() {}`)})
+	head := r.commit(map[string]string{"m.go": packageSource(`func G() {}`)})
+	r.tag("v1.0.0", base)
+	r.tag("v1.1.0", head)
+
+	// A directory can't be created inside a file, which makes storing the
+	// changes fail even for users allowed to write read-only directories.
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var warnings []string
+	cache := DiffCache{
+		Dir: filepath.Join(file, "cache"),
+		Warn: func(msg string) {
+			warnings = append(warnings, msg)
+		},
+	}
+
+	changes, err := DiffRefsCached(r.dir, "v1.0.0", "v1.1.0", cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertChanges(t, changes, []string{
+		"example.com/m: function F: was removed",
+		"example.com/m: function G: was added",
+	})
+
+	want := "unable to cache changes between v1.0.0 and v1.1.0: unable to create cache directory"
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], want) {
+		t.Errorf("expected a warning starting with %q, got %q", want, warnings)
+	}
+}
@@ -51,6 +51,28 @@ func (d DeclType) String() string {
 	}
 }
 
+// slug returns a short name of the declaration type used in identifiers.
+func (d DeclType) slug() string {
+	switch d {
+	case VarType:
+		return "var"
+	case ConstType:
+		return "const"
+	case FuncType:
+		return "func"
+	case InterfaceType:
+		return "interface"
+	case StructType:
+		return "struct"
+	case TypeDefType:
+		return "type"
+	case PackageType:
+		return "package"
+	default:
+		return "invalid"
+	}
+}
+
 type Change interface {
 	String() string
 }
@@ -59,10 +81,14 @@ type DeclChange struct {
 	Name    string
 	Type    DeclType
 	Changes []Change
+	// Before and After are the rendered declaration before and after the
+	// change, or empty if it didn't exist.
+	Before string
+	After  string
 }
 
 func NewDeclChange(name string, typ DeclType, changes ...Change) DeclChange {
-	return DeclChange{name, typ, changes}
+	return DeclChange{Name: name, Type: typ, Changes: changes}
 }
 
 func (d DeclChange) String() string {
@@ -106,6 +132,97 @@ func (r ResultChanged) String() string {
 	)
 }
 
+// ErrorReturnAdded is reported when a function starts returning an error,
+// which callers now need to handle.
+type ErrorReturnAdded struct {
+	Pos int
+}
+
+func (e ErrorReturnAdded) String() string {
+	return fmt.Sprintf("error result added at position %d, callers must now handle it", e.Pos)
+}
+
+// ContextArgAdded is reported when a function gains a context.Context as its
+// first argument, a common refactor that breaks every caller.
+type ContextArgAdded struct{}
+
+func (ContextArgAdded) String() string {
+	return "context.Context argument prepended, callers must now pass a context"
+}
+
+// EnumValueRemoved is reported along with the removal of a constant of a
+// named type with several constants, which is likely one of the values of
+// an enum that switch statements and stored values may rely on.
+type EnumValueRemoved struct {
+	Enum string
+}
+
+func (e EnumValueRemoved) String() string {
+	return fmt.Sprintf("was one of the values of the enum type %s", e.Enum)
+}
+
+// SentinelErrorRemoved is reported along with the removal of a variable
+// holding an error, which is likely a sentinel error users compare errors
+// with, e.g. errors.Is(err, pkg.ErrNotFound). Those comparisons no longer
+// compile and code handling the error specifically has to be rethought.
+type SentinelErrorRemoved struct{}
+
+func (SentinelErrorRemoved) String() string {
+	return "was a sentinel error, comparisons with it using errors.Is no longer compile"
+}
+
+// VariadicChanged is reported when the last argument of a function changes
+// between variadic and a slice of the same type, e.g. F(xs ...int) becoming
+// F(xs []int), a common migration that breaks callers anyway, since they
+// must pass a slice instead of separate values or the other way around.
+type VariadicChanged struct {
+	// Variadic reports whether the argument is now variadic.
+	Variadic bool
+}
+
+func (v VariadicChanged) String() string {
+	if v.Variadic {
+		return "became variadic, callers passing a slice must now expand it with ..."
+	}
+	return "is no longer variadic, callers must now pass a slice instead of separate values"
+}
+
+// OpacityChanged is reported when a struct goes from having exported fields
+// to having none, which makes it opaque, since users can no longer set or
+// read any of its fields, or the other way around, which exposes fields of
+// a struct users could only handle through its methods.
+type OpacityChanged struct {
+	// Opaque reports whether the struct no longer has exported fields.
+	Opaque bool
+}
+
+func (o OpacityChanged) String() string {
+	if o.Opaque {
+		return "became opaque, it no longer has exported fields"
+	}
+	return "is no longer opaque, it now has exported fields"
+}
+
+// ErrorReturnRemoved is reported when a function no longer returns an error.
+type ErrorReturnRemoved struct {
+	Pos int
+}
+
+func (e ErrorReturnRemoved) String() string {
+	return fmt.Sprintf("error result at position %d was removed", e.Pos)
+}
+
+// CleanupReturnAdded is reported when a function starts returning a cleanup
+// function, e.g. Open() *DB becoming Open() (*DB, func()), which callers
+// now need to receive and call.
+type CleanupReturnAdded struct {
+	Pos int
+}
+
+func (c CleanupReturnAdded) String() string {
+	return fmt.Sprintf("cleanup function result added at position %d, callers must now call it when done", c.Pos)
+}
+
 type FieldChanged struct {
 	Pos     int
 	Name    string
@@ -121,6 +238,26 @@ func (f FieldChanged) String() string {
 	)
 }
 
+// PromotedFieldRemoved is reported when a field promoted from an embedded
+// field of a struct is no longer accessible as a field of the struct.
+type PromotedFieldRemoved struct {
+	Name     string
+	Embedded string
+}
+
+func (p PromotedFieldRemoved) String() string {
+	return fmt.Sprintf("field %q promoted from embedded field %s was removed", p.Name, p.Embedded)
+}
+
+type MethodChanged struct {
+	Name    string
+	Changes []Change
+}
+
+func (m MethodChanged) String() string {
+	return fmt.Sprintf("method %s: %s", m.Name, joinChanges(m.Changes))
+}
+
 type TypeChanged struct {
 	From types.Type
 	To   types.Type
@@ -130,12 +267,141 @@ func (tc TypeChanged) String() string {
 	return fmt.Sprintf("type changed from %q to %q", tc.From, tc.To)
 }
 
+// ErrorTypeHidden is reported when a result changes from a concrete type to
+// the error interface, which breaks the callers using the concrete type,
+// e.g. accessing its fields.
+type ErrorTypeHidden struct {
+	From types.Type
+}
+
+func (e ErrorTypeHidden) String() string {
+	return fmt.Sprintf("type changed from concrete type %q to the error interface, callers can no longer use it without a type assertion", e.From)
+}
+
+// ChannelChanged is reported when the type of a package-level variable
+// changes from or to a channel, or between channels.
+type ChannelChanged struct {
+	From types.Type
+	To   types.Type
+	// FromChan and ToChan report whether From and To are channels, which
+	// is kept apart from the types because types read back from JSON
+	// can't be inspected.
+	FromChan bool
+	ToChan   bool
+	// DirChanged reports whether only the direction of the channel changed,
+	// from FromDir to ToDir.
+	DirChanged bool
+	FromDir    types.ChanDir
+	ToDir      types.ChanDir
+}
+
+// newChannelChanged returns the change between the given types, one of
+// which must be a channel.
+func newChannelChanged(from, to types.Type) ChannelChanged {
+	c := ChannelChanged{From: from, To: to}
+	fromChan, ok1 := from.Underlying().(*types.Chan)
+	toChan, ok2 := to.Underlying().(*types.Chan)
+	c.FromChan, c.ToChan = ok1, ok2
+	if ok1 && ok2 && fromChan.Dir() != toChan.Dir() && typeKey(fromChan.Elem(), nil) == typeKey(toChan.Elem(), nil) {
+		c.DirChanged, c.FromDir, c.ToDir = true, fromChan.Dir(), toChan.Dir()
+	}
+	return c
+}
+
+func (c ChannelChanged) String() string {
+	switch {
+	case c.FromChan && !c.ToChan:
+		return fmt.Sprintf("changed from channel type %q to non-channel type %q", c.From, c.To)
+	case !c.FromChan && c.ToChan:
+		return fmt.Sprintf("changed from non-channel type %q to channel type %q", c.From, c.To)
+	case c.DirChanged:
+		return fmt.Sprintf("channel direction changed from %s to %s", chanDirString(c.FromDir), chanDirString(c.ToDir))
+	default:
+		return fmt.Sprintf("channel type changed from %q to %q", c.From, c.To)
+	}
+}
+
+func chanDirString(dir types.ChanDir) string {
+	switch dir {
+	case types.SendOnly:
+		return "send-only"
+	case types.RecvOnly:
+		return "receive-only"
+	default:
+		return "bidirectional"
+	}
+}
+
+// PointerChanged is reported when an argument or a result changes from a
+// value of a named type to a pointer to the same type or vice versa.
+type PointerChanged struct {
+	From types.Type
+	To   types.Type
+	// Result reports whether the change is of a result instead of an
+	// argument.
+	Result bool
+}
+
+func (p PointerChanged) String() string {
+	var param = "parameter"
+	if p.Result {
+		param = "result"
+	}
+
+	if _, ok := p.To.(*types.Pointer); ok {
+		return fmt.Sprintf("%s changed from value to pointer (%q to %q)", param, p.From, p.To)
+	}
+	return fmt.Sprintf("%s changed from pointer to value (%q to %q)", param, p.From, p.To)
+}
+
+// ResultMayBeNil is reported along with the change of a result from a value
+// of a named type to a pointer to it, which introduces nil as a possible
+// result that callers didn't have to handle before.
+type ResultMayBeNil struct{}
+
+func (ResultMayBeNil) String() string {
+	return "result may now be nil, callers must check it before using it"
+}
+
+// TypeSetChanged is reported when the types allowed by a constraint
+// interface change.
+type TypeSetChanged struct {
+	From []types.Type
+	To   []types.Type
+}
+
+func (t TypeSetChanged) String() string {
+	return fmt.Sprintf(
+		"type set changed from %q to %q",
+		typeSetString(t.From),
+		typeSetString(t.To),
+	)
+}
+
+// UnexportedTypeReferenced is reported when a type changes to refer to an
+// unexported type, e.g. the element of a slice becomes unexported, which
+// users can no longer name.
+type UnexportedTypeReferenced struct {
+	Type string
+}
+
+func (u UnexportedTypeReferenced) String() string {
+	return fmt.Sprintf("now refers to unexported type %s, which users can't name", u.Type)
+}
+
 type PositionChanged struct {
 	From int
 	To   int
+	// Keyed reports whether the position is of a field of a struct with
+	// unexported fields, which users can only build with keyed composite
+	// literals, so the change doesn't affect them.
+	Keyed bool
 }
 
 func (p PositionChanged) String() string {
+	if p.Keyed {
+		return fmt.Sprintf("position changed from %d to %d, but the struct can only be built with keyed literals", p.From, p.To)
+	}
 	return fmt.Sprintf("position changed from %d to %d", p.From, p.To)
 }
 
@@ -147,6 +413,81 @@ type Added struct{}
 
 func (Added) String() string { return "was added" }
 
+// Renamed is reported when a declaration seems to have been renamed, that
+// is, it was removed and another one with a similar name and the same shape
+// was added.
+type Renamed struct {
+	From string
+	To   string
+}
+
+func (r Renamed) String() string {
+	return fmt.Sprintf("was probably renamed from %s to %s", r.From, r.To)
+}
+
+// NameChanged is reported when a package changes its name while keeping its
+// import path, which breaks the code referring to it by its name, such as
+// dot-imports and generated code.
+type NameChanged struct {
+	From string
+	To   string
+}
+
+func (n NameChanged) String() string {
+	return fmt.Sprintf("name changed from %s to %s", n.From, n.To)
+}
+
+// AliasTargetChanged is reported for an alias whose declaration did not
+// change when the declaration of the type it refers to did.
+type AliasTargetChanged struct {
+	Target  string
+	Changes []Change
+}
+
+func (a AliasTargetChanged) String() string {
+	return fmt.Sprintf("aliased type %s changed: %s", a.Target, joinChanges(a.Changes))
+}
+
+// AliasRetargeted is reported when an alias refers to a different type, e.g.
+// type A = X becoming type A = Y. Values of the previous type can no longer
+// be used as values of the alias.
+type AliasRetargeted struct {
+	From types.Type
+	To   types.Type
+}
+
+func (a AliasRetargeted) String() string {
+	return fmt.Sprintf("alias target changed from %s to %s", typeString(a.From), typeString(a.To))
+}
+
+// AliasChanged is reported when a defined type becomes an alias, which
+// changes its method set and makes it identical to the aliased type, or an
+// alias becomes a defined type, which is no longer identical to the type it
+// referred to.
+type AliasChanged struct {
+	// Alias reports whether the type is now an alias.
+	Alias bool
+	Type  types.Type
+}
+
+func (a AliasChanged) String() string {
+	if a.Alias {
+		return fmt.Sprintf("became an alias of %s", typeString(a.Type))
+	}
+	return fmt.Sprintf("is no longer an alias, now a defined type with underlying type %s", typeString(a.Type))
+}
+
+// KindChanged is reported when a declaration is replaced by another kind of
+// declaration with the same name, e.g. a variable by a function.
+type KindChanged struct {
+	From DeclType
+	To   DeclType
+}
+
+func (k KindChanged) String() string {
+	return fmt.Sprintf("changed from %s to %s", k.From, k.To)
+}
+
 type ValueChanged struct {
 	From string
 	To   string
@@ -156,24 +497,391 @@ func (v ValueChanged) String() string {
 	return fmt.Sprintf("value changed from %s to %s", v.From, v.To)
 }
 
-func IsBreaking(change Change) bool {
+// IotaShifted is a note reported along with the change of the value of a
+// constant when other constants of the same type were shifted by the same
+// amount, which usually means a constant was inserted or removed in the
+// middle of an iota block. Persisted values of the constants are no longer
+// valid.
+type IotaShifted struct {
+	Delta int64
+}
+
+func (i IotaShifted) String() string {
+	return fmt.Sprintf("shifted by %d along with other constants, probably by a change in an iota block", i.Delta)
+}
+
+// Unexported is a note reported along with the removal of an exported
+// declaration that is still there, but unexported with the given name.
+type Unexported struct {
+	Name string
+}
+
+func (u Unexported) String() string {
+	return fmt.Sprintf("was unexported as %s", u.Name)
+}
+
+// FunctionalOptionsRefactor is a note reported along with the changes of a
+// function whose arguments were collapsed into variadic functional options
+// of the given type.
+type FunctionalOptionsRefactor struct {
+	Options types.Type
+}
+
+func (f FunctionalOptionsRefactor) String() string {
+	return fmt.Sprintf("arguments were probably replaced by functional options of type %s", typeString(f.Options))
+}
+
+// WasDeprecated is a note reported along with the removal of a package that
+// was documented as deprecated.
+type WasDeprecated struct{}
+
+func (WasDeprecated) String() string {
+	return "was deprecated"
+}
+
+// ParamRenamed is a cosmetic change of the name of a parameter.
+type ParamRenamed struct {
+	From string
+	To   string
+}
+
+func (p ParamRenamed) String() string {
+	return fmt.Sprintf("renamed from %q to %q", p.From, p.To)
+}
+
+// ResultRenamed is a cosmetic change of the name of a result, including
+// named results becoming unnamed and the other way around. Result names
+// are only visible in the documentation of the function.
+type ResultRenamed struct {
+	From string
+	To   string
+}
+
+func (r ResultRenamed) String() string {
+	switch {
+	case r.From == "":
+		return fmt.Sprintf("now named %q", r.To)
+	case r.To == "":
+		return fmt.Sprintf("no longer named %q", r.From)
+	default:
+		return fmt.Sprintf("renamed from %q to %q", r.From, r.To)
+	}
+}
+
+// DocChanged is a cosmetic change of the doc comment of a declaration.
+type DocChanged struct {
+	From string
+	To   string
+}
+
+func (DocChanged) String() string { return "doc comment changed" }
+
+// ImplementationsBroken is reported along with changes in interfaces that
+// make existing implementations of the interface no longer satisfy it.
+type ImplementationsBroken struct{}
+
+func (ImplementationsBroken) String() string {
+	return "existing implementations no longer satisfy the interface"
+}
+
+// ConstructorRemoved is reported when the constructor of an exported struct
+// without exported fields is removed and no other function or method
+// returns the struct, because users can no longer create usable values of
+// it: composite literals can't set any of its fields.
+type ConstructorRemoved struct {
+	Constructor string
+}
+
+func (c ConstructorRemoved) String() string {
+	return fmt.Sprintf(
+		"constructor %s was removed and the struct has no exported fields, so it can no longer be created",
+		c.Constructor,
+	)
+}
+
+// MethodShadowed is a note reported when a struct defines a method that was
+// promoted from an embedded field, which callers may rely on.
+type MethodShadowed struct {
+	Embedded string
+}
+
+func (m MethodShadowed) String() string {
+	return fmt.Sprintf("now shadows the method promoted from the embedded field %s", m.Embedded)
+}
+
+// ConstructorNote is reported along with the addition of a field to a
+// struct that has a constructor. The constructor probably initialises the
+// new field, so its zero value may not be valid for users creating the
+// struct with a composite literal instead.
+type ConstructorNote struct {
+	Constructor string
+}
+
+func (c ConstructorNote) String() string {
+	return fmt.Sprintf(
+		"struct has constructor %s, check the zero value of the field is valid for composite literals",
+		c.Constructor,
+	)
+}
+
+// UnkeyedLiteralBroken is reported along with the addition of a struct field
+// when field additions are treated strictly, because composite literals of
+// the struct without field names will no longer compile.
+type UnkeyedLiteralBroken struct{}
+
+func (UnkeyedLiteralBroken) String() string {
+	return "breaks composite literals without field names"
+}
+
+// Severity of a change regarding the compatibility of the API.
+type Severity byte
+
+const (
+	// Cosmetic changes don't affect the API, e.g. renamed parameters.
+	Cosmetic Severity = iota
+	// Additive changes extend the API in a backwards compatible way.
+	Additive
+	// Breaking changes are not backwards compatible.
+	Breaking
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Cosmetic:
+		return "cosmetic"
+	case Additive:
+		return "additive"
+	case Breaking:
+		return "breaking"
+	default:
+		return "INVALID"
+	}
+}
+
+// SeverityOf returns the severity of the given change. Changes of unknown
+// kinds are considered additive.
+func SeverityOf(change Change) Severity {
+	if s, ok := severityOf(change); ok {
+		return s
+	}
+	return Additive
+}
+
+// severityOf returns the severity of the given change, or false if the kind
+// of the change is not classified. Every kind of change must be classified
+// here.
+func severityOf(change Change) (Severity, bool) {
 	switch c := change.(type) {
 	case Removed,
-		PositionChanged,
+		ValueChanged,
 		TypeChanged,
-		FieldChanged,
-		ResultChanged,
-		ArgumentChanged:
-		return true
+		TypeArgumentChanged,
+		PointerChanged,
+		ResultMayBeNil,
+		ErrorTypeHidden,
+		ChannelChanged,
+		TypeSetChanged,
+		UnexportedTypeReferenced,
+		ErrorReturnAdded,
+		ErrorReturnRemoved,
+		EnumValueRemoved,
+		SentinelErrorRemoved,
+		VariadicChanged,
+		ContextArgAdded,
+		CleanupReturnAdded,
+		PromotedFieldRemoved,
+		KindChanged,
+		Renamed,
+		NameChanged,
+		ImplementationsBroken,
+		InterfaceLost,
+		ValueInterfaceLost,
+		AliasRetargeted,
+		AliasChanged,
+		ConstraintNarrowed,
+		ConstructorRemoved,
+		ArityChanged,
+		TypeParamsReordered,
+		UnkeyedLiteralBroken:
+		return Breaking, true
+	case ParamRenamed,
+		ResultRenamed,
+		DocChanged,
+		ConstructorNote,
+		MethodShadowed,
+		IotaShifted,
+		WasDeprecated,
+		Unexported,
+		FunctionalOptionsRefactor,
+		FoundIn,
+		ResultNarrowed,
+		ResultWidened,
+		StdNameShadowed,
+		PanicAdded:
+		return Cosmetic, true
+	case TagOptionChanged:
+		return c.Severity, true
+	case AliasSubstituted:
+		return c.Severity, true
+	case Added,
+		ArgumentWidened,
+		ConstraintWidened:
+		return Additive, true
+	case OpacityChanged:
+		if c.Opaque {
+			return Breaking, true
+		}
+		return Additive, true
+	case PositionChanged:
+		if c.Keyed {
+			return Cosmetic, true
+		}
+		return Breaking, true
+	case ReceiverChanged:
+		if c.Pointer {
+			return Breaking, true
+		}
+		return Cosmetic, true
+	case ResultChanged:
+		return paramSeverity(c.Changes), true
+	case ArgumentChanged:
+		return paramSeverity(c.Changes), true
+	case TypeParamChanged:
+		return maxSeverity(c.Changes), true
+	case FieldChanged:
+		return maxSeverity(c.Changes), true
+	case MethodChanged:
+		return maxSeverity(c.Changes), true
+	case AliasTargetChanged:
+		return maxSeverity(c.Changes), true
 	case DeclChange:
-		for _, c := range c.Changes {
-			if IsBreaking(c) {
-				return true
+		return maxSeverity(c.Changes), true
+	}
+
+	return 0, false
+}
+
+// IsBreaking reports whether the given change is a breaking change.
+func IsBreaking(change Change) bool {
+	return SeverityOf(change) == Breaking
+}
+
+func maxSeverity(cs []Change) Severity {
+	var result = Cosmetic
+	for _, c := range cs {
+		if s := SeverityOf(c); s > result {
+			result = s
+		}
+	}
+	return result
+}
+
+// paramSeverity returns the severity of the changes of a function argument
+// or result. Any change to them that is not cosmetic, even an addition,
+// breaks the callers of the function, except for the ones that don't
+// affect them: identical types written with different aliases and widened
+// arguments.
+func paramSeverity(cs []Change) Severity {
+	var result = Cosmetic
+	for _, c := range cs {
+		s := SeverityOf(c)
+		switch c.(type) {
+		case AliasSubstituted, ArgumentWidened:
+		default:
+			if s > Cosmetic {
+				s = Breaking
 			}
 		}
+
+		if s > result {
+			result = s
+		}
+	}
+	return result
+}
+
+// Bump is the kind of version bump required by a set of changes.
+type Bump byte
+
+const (
+	// PatchBump is required when the API did not change or the changes are
+	// cosmetic.
+	PatchBump Bump = iota
+	// MinorBump is required when the API was extended.
+	MinorBump
+	// MajorBump is required when there are breaking changes.
+	MajorBump
+)
+
+func (b Bump) String() string {
+	switch b {
+	case PatchBump:
+		return "patch"
+	case MinorBump:
+		return "minor"
+	case MajorBump:
+		return "major"
+	default:
+		return "INVALID"
+	}
+}
+
+// Recommend returns the version bump required by the given changes.
+func Recommend(changes APIChanges) Bump {
+	return Policy{}.Recommend(changes)
+}
+
+// InitialVersion returns the version recommended for the first release of a
+// project given the changes of its API against an empty one, as if it was
+// released as v0.0.0, which makes every package added. It's v0.1.0 if the
+// project has an API, following the convention of starting with major
+// version 0 while the API is not stable, and v0.0.1 otherwise.
+func InitialVersion(changes APIChanges) string {
+	if Recommend(changes) == PatchBump {
+		return "v0.0.1"
+	}
+	return "v0.1.0"
+}
+
+// Walk calls fn for every change, including the changes nested inside other
+// changes, along with the package the change belongs to. Nested changes are
+// not walked if fn returns false.
+func (c APIChanges) Walk(fn func(pkg PackageChanges, change Change) bool) {
+	for _, pkg := range c {
+		walkChanges(pkg.Changes, func(change Change) bool {
+			return fn(pkg, change)
+		})
 	}
+}
 
-	return false
+func walkChanges(cs []Change, fn func(Change) bool) {
+	for _, c := range cs {
+		if fn(c) {
+			walkChanges(nestedChanges(c), fn)
+		}
+	}
+}
+
+// nestedChanges returns the changes contained in the given change.
+func nestedChanges(c Change) []Change {
+	switch c := c.(type) {
+	case DeclChange:
+		return c.Changes
+	case ArgumentChanged:
+		return c.Changes
+	case TypeParamChanged:
+		return c.Changes
+	case ResultChanged:
+		return c.Changes
+	case FieldChanged:
+		return c.Changes
+	case MethodChanged:
+		return c.Changes
+	case AliasTargetChanged:
+		return c.Changes
+	default:
+		return nil
+	}
 }
 
 func joinChanges(cs []Change) string {
@@ -188,3 +896,11 @@ func typeString(t types.Type) string {
 	// TODO: check actual printing here is what's required for display
 	return t.String()
 }
+
+func typeSetString(ts []types.Type) string {
+	var strs = make([]string, len(ts))
+	for i, t := range ts {
+		strs[i] = typeString(t)
+	}
+	return strings.Join(strs, "; ")
+}
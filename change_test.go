@@ -0,0 +1,55 @@
+package semverlint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInitialVersion(t *testing.T) {
+	current := moduleAPI(t, map[string]string{
+		"m.go": packageSource(`
+type T struct{ X int }
+
+func (T) M() {}
+
+func F() {}
+
+const C = 1
+`),
+		"a/a.go": "package a\n\nvar V int\n",
+		"b/b.go": "package b\n",
+	})
+
+	changes := Diff(current, nil)
+	AssertChanges(t, changes, []string{
+		`example.com/m: package m: was added`,
+		`example.com/m/a: package a: was added`,
+		`example.com/m/b: package b: was added`,
+	})
+
+	changes.Walk(func(pkg PackageChanges, c Change) bool {
+		if s := SeverityOf(c); s != Additive {
+			t.Errorf("%s: expected %s to be additive, got %s", pkg.Path, c, s)
+		}
+		return false
+	})
+
+	if v := InitialVersion(changes); v != "v0.1.0" {
+		t.Errorf("expected initial version v0.1.0, got %s", v)
+	}
+
+	if v := InitialVersion(Diff(nil, nil)); v != "v0.0.1" {
+		t.Errorf("expected initial version v0.0.1 without an API, got %s", v)
+	}
+}
+
+// TestSeverityOfKinds checks that every kind of change has an explicit
+// severity, so new kinds can't silently be considered additive.
+func TestSeverityOfKinds(t *testing.T) {
+	for name, typ := range changeKinds {
+		c := reflect.Zero(typ).Interface().(Change)
+		if _, ok := severityOf(c); !ok {
+			t.Errorf("change %s has no severity", name)
+		}
+	}
+}
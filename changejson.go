@@ -0,0 +1,251 @@
+package semverlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io"
+	"reflect"
+)
+
+// changeKinds are the types of the changes that can be encoded by
+// WriteChanges by the name of the type. Every change must be listed here.
+var changeKinds = kindsOf(
+	DeclChange{},
+	ArgumentChanged{},
+	ResultChanged{},
+	TypeParamChanged{},
+	FieldChanged{},
+	MethodChanged{},
+	AliasTargetChanged{},
+	AliasRetargeted{},
+	AliasChanged{},
+	AliasSubstituted{},
+	ArgumentWidened{},
+	ConstructorRemoved{},
+	StdNameShadowed{},
+	ArityChanged{},
+	TypeParamsReordered{},
+	PanicAdded{},
+	ErrorReturnAdded{},
+	ErrorReturnRemoved{},
+	EnumValueRemoved{},
+	SentinelErrorRemoved{},
+	ResultMayBeNil{},
+	VariadicChanged{},
+	ContextArgAdded{},
+	CleanupReturnAdded{},
+	PromotedFieldRemoved{},
+	OpacityChanged{},
+	TypeChanged{},
+	TypeArgumentChanged{},
+	ErrorTypeHidden{},
+	ChannelChanged{},
+	PointerChanged{},
+	TypeSetChanged{},
+	UnexportedTypeReferenced{},
+	PositionChanged{},
+	Removed{},
+	Added{},
+	Renamed{},
+	NameChanged{},
+	KindChanged{},
+	ValueChanged{},
+	IotaShifted{},
+	Unexported{},
+	FunctionalOptionsRefactor{},
+	WasDeprecated{},
+	ParamRenamed{},
+	ResultRenamed{},
+	DocChanged{},
+	ImplementationsBroken{},
+	MethodShadowed{},
+	ConstructorNote{},
+	UnkeyedLiteralBroken{},
+	ConstraintNarrowed{},
+	ConstraintWidened{},
+	InterfaceLost{},
+	ReceiverChanged{},
+	ValueInterfaceLost{},
+	TagOptionChanged{},
+	FoundIn{},
+	ResultNarrowed{},
+	ResultWidened{},
+)
+
+func kindsOf(changes ...Change) map[string]reflect.Type {
+	var result = make(map[string]reflect.Type, len(changes))
+	for _, c := range changes {
+		t := reflect.TypeOf(c)
+		result[t.Name()] = t
+	}
+	return result
+}
+
+var (
+	typeType   = reflect.TypeOf((*types.Type)(nil)).Elem()
+	changeType = reflect.TypeOf((*Change)(nil)).Elem()
+)
+
+type packageChangesJSON struct {
+	Name    string       `json:"name"`
+	Path    string       `json:"path"`
+	Changes []changeJSON `json:"changes"`
+}
+
+// changeJSON is the JSON representation of a change, made of the name of
+// its type and its fields.
+type changeJSON struct {
+	Kind   string                     `json:"kind"`
+	Fields map[string]json.RawMessage `json:"fields,omitempty"`
+}
+
+// WriteChanges writes the given changes as JSON, so they can be read back
+// with ReadChanges. Types are written as in WriteAPI.
+func WriteChanges(w io.Writer, changes APIChanges) error {
+	var pkgs = make([]packageChangesJSON, len(changes))
+	for i, pkg := range changes {
+		pkgs[i] = packageChangesJSON{Name: pkg.Name, Path: pkg.Path}
+		for _, c := range pkg.Changes {
+			cj, err := encodeChange(c)
+			if err != nil {
+				return err
+			}
+			pkgs[i].Changes = append(pkgs[i].Changes, cj)
+		}
+	}
+	return json.NewEncoder(w).Encode(pkgs)
+}
+
+// ReadChanges reads the changes written by WriteChanges. Types are read as
+// in ReadAPI.
+func ReadChanges(r io.Reader) (APIChanges, error) {
+	var pkgs []packageChangesJSON
+	if err := json.NewDecoder(r).Decode(&pkgs); err != nil {
+		return nil, fmt.Errorf("unable to decode changes: %s", err)
+	}
+
+	var result = make(APIChanges, len(pkgs))
+	for i, pkg := range pkgs {
+		result[i] = PackageChanges{Name: pkg.Name, Path: pkg.Path}
+		for _, cj := range pkg.Changes {
+			c, err := decodeChange(cj)
+			if err != nil {
+				return nil, err
+			}
+			result[i].Changes = append(result[i].Changes, c)
+		}
+	}
+	return result, nil
+}
+
+func encodeChange(c Change) (changeJSON, error) {
+	v := reflect.ValueOf(c)
+	t := v.Type()
+	if _, ok := changeKinds[t.Name()]; !ok {
+		return changeJSON{}, fmt.Errorf("unable to encode unknown change type %s", t)
+	}
+
+	var result = changeJSON{Kind: t.Name(), Fields: make(map[string]json.RawMessage)}
+	for i := 0; i < t.NumField(); i++ {
+		raw, err := encodeValue(v.Field(i))
+		if err != nil {
+			return changeJSON{}, err
+		}
+		result.Fields[t.Field(i).Name] = raw
+	}
+	return result, nil
+}
+
+func encodeValue(v reflect.Value) (json.RawMessage, error) {
+	switch {
+	case v.Type() == typeType:
+		var t types.Type
+		if !v.IsNil() {
+			t = v.Interface().(types.Type)
+		}
+		return json.Marshal(newTypeJSON(t))
+	case v.Type() == changeType:
+		c, err := encodeChange(v.Interface().(Change))
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(c)
+	case v.Kind() == reflect.Slice && (v.Type().Elem() == typeType || v.Type().Elem() == changeType):
+		var elems = make([]json.RawMessage, v.Len())
+		for i := range elems {
+			raw, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = raw
+		}
+		return json.Marshal(elems)
+	default:
+		return json.Marshal(v.Interface())
+	}
+}
+
+func decodeChange(cj changeJSON) (Change, error) {
+	t, ok := changeKinds[cj.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unable to decode unknown change type %s", cj.Kind)
+	}
+
+	v := reflect.New(t).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		raw, ok := cj.Fields[t.Field(i).Name]
+		if !ok {
+			continue
+		}
+
+		if err := decodeValue(raw, v.Field(i)); err != nil {
+			return nil, fmt.Errorf("unable to decode field %s of %s: %s", t.Field(i).Name, cj.Kind, err)
+		}
+	}
+	return v.Interface().(Change), nil
+}
+
+func decodeValue(raw json.RawMessage, v reflect.Value) error {
+	switch {
+	case v.Type() == typeType:
+		var tj *typeJSON
+		if err := json.Unmarshal(raw, &tj); err != nil {
+			return err
+		}
+		if t := tj.typ(); t != nil {
+			v.Set(reflect.ValueOf(t))
+		}
+		return nil
+	case v.Type() == changeType:
+		var cj changeJSON
+		if err := json.Unmarshal(raw, &cj); err != nil {
+			return err
+		}
+		c, err := decodeChange(cj)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(c))
+		return nil
+	case v.Kind() == reflect.Slice && (v.Type().Elem() == typeType || v.Type().Elem() == changeType):
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return err
+		}
+		if elems == nil {
+			return nil
+		}
+
+		s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, e := range elems {
+			if err := decodeValue(e, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	default:
+		return json.Unmarshal(raw, v.Addr().Interface())
+	}
+}
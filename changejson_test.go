@@ -0,0 +1,78 @@
+package semverlint
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadChanges(t *testing.T) {
+	changes := diffSources(t, `
+type Config struct {
+	Name string
+	Size int
+}
+
+type Store interface {
+	Get(key string) ([]byte, error)
+}
+
+const (
+	A = iota
+	B
+)
+
+var Events chan int
+
+func Open(name string) *Config { return nil }
+func Close() {}
+`, `
+type Config struct {
+	Name string
+	Size int64
+}
+
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+}
+
+const (
+	A = iota
+	C
+	B
+)
+
+var Events []int
+
+func Open(name string, flags int) (*Config, error) { return nil, nil }
+func Flush() {}
+`)
+
+	var b bytes.Buffer
+	if err := WriteChanges(&b, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadChanges(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := read.Strings(), changes.Strings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the same changes after reading them back:\n%s\ngot:\n%s",
+			strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	if got, want := Recommend(read), Recommend(changes); got != want {
+		t.Errorf("expected recommendation %s, got %s", want, got)
+	}
+}
+
+func TestReadChangesUnknownKind(t *testing.T) {
+	_, err := ReadChanges(strings.NewReader(`[{"name":"m","path":"example.com/m","changes":[{"kind":"Unknown"}]}]`))
+	if err == nil || !strings.Contains(err.Error(), "unknown change type Unknown") {
+		t.Errorf("expected an error for an unknown kind, got %v", err)
+	}
+}
@@ -0,0 +1,316 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// ChangedPackages returns the import paths of the packages of the repository
+// at the given path whose API may have changed between two revisions: the
+// packages with files changed between them and the packages importing them,
+// directly or not, since they may expose their types. If go.mod or go.sum
+// changed, the API of every package may have changed, so all of them are
+// returned. The repository must be a module, since the packages are
+// identified by their import paths.
+func ChangedPackages(path, baseRef, headRef string) ([]string, error) {
+	base, err := ResolveVersion(path, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := ResolveVersion(path, headRef)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open repository: %s", err)
+	}
+
+	dirs, err := changedDirs(r, base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commitTree(r, head)
+	if err != nil {
+		return nil, err
+	}
+
+	module, err := treeModulePath(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]string, len(dirs))
+	for i, d := range dirs {
+		result[i] = dirImportPath(module, d)
+	}
+	return result, nil
+}
+
+// DiffRefsFast is like DiffRefs, but only the packages returned by
+// ChangedPackages are loaded and compared, which is much faster for small
+// changes in large repositories.
+func DiffRefsFast(path, baseRef, headRef string) (APIChanges, error) {
+	base, err := ResolveVersion(path, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := ResolveVersion(path, headRef)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open repository: %s", err)
+	}
+
+	dirs, err := changedDirs(r, base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing to load, and loading with no directories loads them all.
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+
+	opts := LoadOptions{Dirs: dirs}
+	prev, err := VersionAPIWithOptions(path, base, opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get API of %s: %s", base.Name, err)
+	}
+
+	current, err := VersionAPIWithOptions(path, head, opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get API of %s: %s", head.Name, err)
+	}
+
+	return Diff(current, prev), nil
+}
+
+// changedDirs returns the directories, relative to the root of the
+// repository, of the packages whose API may have changed between the given
+// versions. See ChangedPackages.
+func changedDirs(r *git.Repository, base, head Version) ([]string, error) {
+	baseTree, err := commitTree(r, base)
+	if err != nil {
+		return nil, err
+	}
+
+	headTree, err := commitTree(r, head)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, fmt.Errorf("unable to diff %s and %s: %s", base.Name, head.Name, err)
+	}
+
+	var prevPkgs, currentPkgs treePackages
+	if prevPkgs, err = packagesOfTree(baseTree); err != nil {
+		return nil, err
+	}
+
+	if currentPkgs, err = packagesOfTree(headTree); err != nil {
+		return nil, err
+	}
+
+	// The imports of the packages are unknown without a module, so the
+	// packages importing the changed ones can't be known either.
+	_, err = treeModulePath(headTree)
+	if err != nil && err != ErrNoModule {
+		return nil, err
+	}
+
+	var changed = make(map[string]struct{})
+	var all = err == ErrNoModule
+	for _, c := range changes {
+		for _, name := range []string{c.From.Name, c.To.Name} {
+			switch {
+			case name == "":
+			case name == "go.mod" || name == "go.sum":
+				all = true
+			case strings.HasSuffix(name, "_test.go"):
+			default:
+				// Any file of a package, not only Go files, may change
+				// its API, e.g. files with C code or embedded files.
+				changed[path.Dir(name)] = struct{}{}
+			}
+		}
+	}
+
+	if all {
+		for _, pkgs := range []treePackages{prevPkgs, currentPkgs} {
+			for dir := range pkgs {
+				changed[dir] = struct{}{}
+			}
+		}
+	}
+
+	// Packages importing a changed package may expose its types, so they
+	// are changed as well.
+	for {
+		var added bool
+		for _, pkgs := range []treePackages{prevPkgs, currentPkgs} {
+			for dir, imports := range pkgs {
+				if _, ok := changed[dir]; ok {
+					continue
+				}
+
+				for _, imp := range imports {
+					if _, ok := changed[imp]; ok {
+						changed[dir] = struct{}{}
+						added = true
+						break
+					}
+				}
+			}
+		}
+
+		if !added {
+			break
+		}
+	}
+
+	var result []string
+	for dir := range changed {
+		_, prevOk := prevPkgs[dir]
+		_, currentOk := currentPkgs[dir]
+		if prevOk || currentOk {
+			result = append(result, dir)
+		}
+	}
+
+	sort.Strings(result)
+	return result, nil
+}
+
+func commitTree(r *git.Repository, v Version) (*object.Tree, error) {
+	commit, err := r.CommitObject(v.Commit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get commit of %s: %s", v.Name, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tree of %s: %s", v.Name, err)
+	}
+	return tree, nil
+}
+
+// treePackages are the directories of the packages in a tree along with the
+// directories of the packages of the same module they import.
+type treePackages map[string][]string
+
+// packagesOfTree returns the packages in the given tree, skipping the same
+// directories skipped by default when loading a project. If the tree is not
+// a module, the imports of the packages are not recorded.
+func packagesOfTree(tree *object.Tree) (treePackages, error) {
+	module, err := treeModulePath(tree)
+	if err != nil && err != ErrNoModule {
+		return nil, err
+	}
+
+	var skipped = map[string]struct{}{"vendor": {}}
+	for _, name := range defaultSkipDirs {
+		skipped[name] = struct{}{}
+	}
+
+	var result = make(treePackages)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if !strings.HasSuffix(f.Name, ".go") || strings.HasSuffix(f.Name, "_test.go") {
+			return nil
+		}
+
+		dir := path.Dir(f.Name)
+		for _, part := range strings.Split(dir, "/") {
+			if _, ok := skipped[part]; ok {
+				return nil
+			}
+		}
+
+		if _, ok := result[dir]; !ok {
+			result[dir] = nil
+		}
+
+		if module == "" {
+			return nil
+		}
+
+		content, err := f.Contents()
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %s", f.Name, err)
+		}
+
+		// Files that can't be parsed just don't contribute imports.
+		file, err := parser.ParseFile(token.NewFileSet(), f.Name, content, parser.ImportsOnly)
+		if err != nil {
+			return nil
+		}
+
+		for _, imp := range file.Imports {
+			p, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+
+			if p == module {
+				result[dir] = append(result[dir], ".")
+			} else if strings.HasPrefix(p, module+"/") {
+				result[dir] = append(result[dir], strings.TrimPrefix(p, module+"/"))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list packages: %s", err)
+	}
+
+	return result, nil
+}
+
+// treeModulePath returns the path of the module declared in the go.mod file
+// at the root of the given tree.
+func treeModulePath(tree *object.Tree) (string, error) {
+	f, err := tree.File("go.mod")
+	if err == object.ErrFileNotFound {
+		return "", ErrNoModule
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unable to get go.mod: %s", err)
+	}
+
+	content, err := f.Contents()
+	if err != nil {
+		return "", fmt.Errorf("unable to read go.mod: %s", err)
+	}
+
+	module := modfile.ModulePath([]byte(content))
+	if module == "" {
+		return "", fmt.Errorf("no module path declared in go.mod")
+	}
+	return module, nil
+}
+
+func dirImportPath(module, dir string) string {
+	if dir == "." {
+		return module
+	}
+	return module + "/" + dir
+}
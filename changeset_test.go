@@ -0,0 +1,81 @@
+package semverlint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangedPackages(t *testing.T) {
+	r := newTestRepo(t)
+	r.tag("v1.0.0", r.commit(map[string]string{
+		"a/a.go":      "package a\n\ntype A struct{ X int }\n",
+		"b/b.go":      "package b\n\nimport \"example.com/m/a\"\n\nfunc B() a.A { return a.A{} }\n",
+		"c/c.go":      "package c\n\nfunc C() {}\n",
+		"d/d.go":      "package d\n\nimport \"example.com/m/c\"\n\nfunc D() { c.C() }\n",
+		"a/README.md": "a\n",
+	}))
+	r.tag("v1.1.0", r.commit(map[string]string{
+		"a/a.go": "package a\n\ntype A struct{ X int64 }\n",
+	}))
+	r.tag("v1.2.0", r.commit(map[string]string{
+		"c/c_test.go": "package c\n",
+		"a/README.md": "a, but longer\n",
+	}))
+	r.tag("v2.0.0", r.commit(map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.26\n\n// Comment.\n",
+	}))
+
+	testCases := []struct {
+		base, head string
+		want       []string
+	}{
+		{"v1.0.0", "v1.1.0", []string{"example.com/m/a", "example.com/m/b"}},
+		{"v1.1.0", "v1.2.0", []string{"example.com/m/a", "example.com/m/b"}},
+		{"v1.2.0", "v2.0.0", []string{"example.com/m/a", "example.com/m/b", "example.com/m/c", "example.com/m/d"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.base+".."+tc.head, func(t *testing.T) {
+			got, err := ChangedPackages(r.dir, tc.base, tc.head)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected changed packages %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDiffRefsFast(t *testing.T) {
+	r := newTestRepo(t)
+	r.tag("v1.0.0", r.commit(map[string]string{
+		"a/a.go": "package a\n\ntype A struct{ X int }\n",
+		"b/b.go": "package b\n\nimport \"example.com/m/a\"\n\nfunc B() a.A { return a.A{} }\n",
+		"c/c.go": "package c\n\nfunc C() {}\n",
+	}))
+	r.tag("v1.1.0", r.commit(map[string]string{
+		"a/a.go": "package a\n\ntype A struct{ X int64 }\n",
+	}))
+
+	changes, err := DiffRefsFast(r.dir, "v1.0.0", "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertChanges(t, changes, []string{
+		`example.com/m/a: struct A: field "X" at position 0: type changed from "int" to "int64"`,
+	})
+
+	// Every package loaded is in the changes, even without changes, so the
+	// untouched package was not loaded.
+	var paths []string
+	for _, pkg := range changes {
+		paths = append(paths, pkg.Path)
+	}
+
+	if want := []string{"example.com/m/a", "example.com/m/b"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected only packages %v to be analyzed, got %v", want, paths)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/erizocosmico/semverlint"
+)
+
+var errBreakingChanges = errors.New("there are breaking changes")
+
+// check diffs the API of the project against a baseline written by
+// snapshot, or an empty one with -initial, and fails if there are breaking
+// changes, unless -no-fail is set.
+func check(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	path := flags.String("path", ".", "path of the project")
+	baseline := flags.String("baseline", "", "API snapshot to compare against")
+	format := flags.String("format", "text", "output format, one of: "+strings.Join(semverlint.Reporters(), ", "))
+	noFail := flags.Bool("no-fail", false, "report breaking changes without failing")
+	initial := flags.Bool("initial", false, "compare against an empty API, for projects not released yet")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *baseline == "" && !*initial {
+		return errors.New("-baseline or -initial is required")
+	}
+
+	if *baseline != "" && *initial {
+		return errors.New("-baseline and -initial can't be used together")
+	}
+
+	reporter, ok := semverlint.LookupReporter(*format)
+	if !ok {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	var prev semverlint.API
+	if !*initial {
+		f, err := os.Open(*baseline)
+		if err != nil {
+			return fmt.Errorf("unable to open baseline: %s", err)
+		}
+		defer f.Close()
+
+		if prev, err = semverlint.ReadAPI(f); err != nil {
+			return err
+		}
+	}
+
+	current, err := semverlint.ProjectAPIWithOptions(*path, loadOptions)
+	if err != nil {
+		return fmt.Errorf("unable to get API: %s", err)
+	}
+
+	changes := semverlint.Diff(current, prev)
+	if err := reporter.Report(os.Stdout, changes); err != nil {
+		return fmt.Errorf("unable to write report: %s", err)
+	}
+
+	// The report may be written in a machine readable format, so the
+	// recommendation is written apart from it.
+	if *initial {
+		fmt.Fprintf(os.Stderr, "recommended initial version: %s\n", semverlint.InitialVersion(changes))
+	} else {
+		fmt.Fprintf(os.Stderr, "recommended version bump: %s\n", semverlint.Recommend(changes))
+	}
+	if exitCode(changes, *noFail) != 0 {
+		return errBreakingChanges
+	}
+	return nil
+}
+
+// exitCode returns the exit code of a check with the given changes, which
+// fails if there are breaking changes unless noFail is set.
+func exitCode(changes semverlint.APIChanges, noFail bool) int {
+	if noFail || semverlint.Recommend(changes) != semverlint.MajorBump {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/erizocosmico/semverlint"
+)
+
+// captureStdout returns what fn writes to the standard output.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	stdout := os.Stdout
+	os.Stdout = f
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// testModule writes a module whose only file has the given source to a
+// temporary directory and returns its path.
+func testModule(t *testing.T, src string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.26\n",
+		"m.go":   "package m\n\n" + src + "\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSnapshotAndCheck(t *testing.T) {
+	dir := testModule(t, "type T struct{ X int }\n\nfunc F(t T) error { return nil }")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = snapshot([]string{"-path", dir})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseline := filepath.Join(t.TempDir(), "api.json")
+	if err := os.WriteFile(baseline, []byte(out), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out = captureStdout(t, func() {
+		err = check([]string{"-path", dir, "-baseline", baseline, "-format", "json"})
+	})
+	if err != nil {
+		t.Fatalf("expected no error checking the same source, got %s", err)
+	}
+
+	var report []semverlint.PackageReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range report {
+		if len(p.Changes) > 0 {
+			t.Errorf("expected no changes in %s, got %+v", p.Path, p.Changes)
+		}
+	}
+
+	changed := testModule(t, "type T struct{ X int }\n\nfunc F(t *T) error { return nil }")
+	out = captureStdout(t, func() {
+		err = check([]string{"-path", changed, "-baseline", baseline})
+	})
+	if err != errBreakingChanges {
+		t.Errorf("expected breaking changes error, got %v", err)
+	}
+
+	if out == "" {
+		t.Errorf("expected the breaking changes to be reported")
+	}
+}
+
+func TestNoFail(t *testing.T) {
+	prev, err := semverlint.ProjectAPI(testModule(t, "func F() {}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := semverlint.ProjectAPI(testModule(t, "func G() {}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := semverlint.Diff(current, prev)
+	if code := exitCode(changes, false); code != 1 {
+		t.Errorf("expected exit code 1 with breaking changes, got %d", code)
+	}
+
+	if code := exitCode(changes, true); code != 0 {
+		t.Errorf("expected exit code 0 with breaking changes and -no-fail, got %d", code)
+	}
+}
+
+func TestCheckNoFail(t *testing.T) {
+	dir := testModule(t, "func F() {}")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = snapshot([]string{"-path", dir})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseline := filepath.Join(t.TempDir(), "api.json")
+	if err := os.WriteFile(baseline, []byte(out), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := testModule(t, "func G() {}")
+	out = captureStdout(t, func() {
+		err = check([]string{"-path", changed, "-baseline", baseline, "-no-fail"})
+	})
+	if err != nil {
+		t.Errorf("expected no error with -no-fail, got %s", err)
+	}
+
+	if want := "example.com/m: function F: was removed\nexample.com/m: function G: was added\n"; out != want {
+		t.Errorf("expected the full report %q, got %q", want, out)
+	}
+}
+
+func TestCheckInitial(t *testing.T) {
+	dir := testModule(t, "type T struct{ X int }\n\nfunc F(t T) error { return nil }")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = check([]string{"-path", dir, "-initial"})
+	})
+	if err != nil {
+		t.Fatalf("expected no error for an initial API, got %s", err)
+	}
+
+	if want := "example.com/m: package m: was added\n"; out != want {
+		t.Errorf("expected report %q, got %q", want, out)
+	}
+
+	if err := check([]string{"-path", dir}); err == nil {
+		t.Error("expected an error without -baseline or -initial")
+	}
+
+	if err := check([]string{"-path", dir, "-initial", "-baseline", "api.json"}); err == nil {
+		t.Error("expected an error with both -baseline and -initial")
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/erizocosmico/semverlint"
+)
+
+// explain prints a detailed explanation of the change with the given ID, as
+// reported in the JSON output of check, of why it's breaking and how to
+// migrate.
+func explain(args []string) error {
+	flags := flag.NewFlagSet("explain", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: semverlint explain <change-id>")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected a single change ID")
+	}
+
+	explanation, err := semverlint.Explain(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(explanation)
+	return nil
+}
@@ -1,7 +1,53 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+
+	"github.com/erizocosmico/semverlint"
+)
+
+const usage = `usage: semverlint <command> [flags]
+
+commands:
+  snapshot  write the API of a project as JSON to the standard output
+  check     check the API of a project against a snapshot
+  serve     serve the diffs between revisions of repositories over HTTP
+  explain   explain a change reported by check given its ID
+`
+
+// loadOptions are the options used to load the API of projects, which write
+// the warnings found, such as files for other platforms that may contain
+// more API, to the standard error.
+var loadOptions = semverlint.LoadOptions{
+	Warn: func(msg string) {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+	},
+}
 
 func main() {
-	fmt.Println("not implemented yet")
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "snapshot":
+		err = snapshot(args)
+	case "check":
+		err = check(args)
+	case "serve":
+		err = serve(args)
+	case "explain":
+		err = explain(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
 }
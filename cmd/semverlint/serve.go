@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/erizocosmico/semverlint"
+)
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	cacheDir := flags.String("cache", "", "directory where the diffs between commits are cached, disabled if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/diff", diffHandler{cacheDir: *cacheDir})
+	log.Printf("listening on %s", *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+// diffRequest is the body of the requests to diff two revisions of the
+// repository at the given path.
+type diffRequest struct {
+	Path string `json:"path"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type diffResponse struct {
+	Recommend string                     `json:"recommend"`
+	Packages  []semverlint.PackageReport `json:"packages"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// diffHandler responds to POST requests with a diffRequest body with the
+// changes between the two revisions as JSON.
+type diffHandler struct {
+	// cacheDir is the directory where the diffs are cached, if not empty.
+	cacheDir string
+}
+
+func (h diffHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"only POST is allowed"})
+		return
+	}
+
+	var req diffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("invalid request: %s", err)})
+		return
+	}
+
+	if req.Path == "" || req.From == "" || req.To == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"path, from and to are required"})
+		return
+	}
+
+	changes, err := h.diffRevisions(r.Context(), req.Path, req.From, req.To)
+	if err != nil {
+		if r.Context().Err() != nil {
+			// The client is gone, there's no one to respond to.
+			return
+		}
+		writeJSON(w, http.StatusUnprocessableEntity, errorResponse{err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, diffResponse{
+		Recommend: semverlint.Recommend(changes).String(),
+		Packages:  changes.Report(),
+	})
+}
+
+// diffRevisions returns the changes between two revisions of the repository
+// at the given path. Loading the APIs can't be interrupted, so if the context
+// is done before the diff is computed it returns right away, but the diff
+// keeps being computed in the background and cached, if there's a cache,
+// so retrying the request is faster.
+func (h diffHandler) diffRevisions(ctx context.Context, path, from, to string) (semverlint.APIChanges, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		changes semverlint.APIChanges
+		err     error
+	}
+
+	// The channel is buffered so the goroutine doesn't leak if no one
+	// receives the result.
+	done := make(chan result, 1)
+	go func() {
+		var r result
+		if h.cacheDir != "" {
+			r.changes, r.err = semverlint.DiffRefsCached(path, from, to, semverlint.DiffCache{
+				Dir: h.cacheDir,
+				Warn: func(msg string) {
+					log.Printf("warning: %s", msg)
+				},
+			})
+		} else {
+			r.changes, r.err = semverlint.DiffRefs(path, from, to)
+		}
+		done <- r
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.changes, r.err
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("unable to write response: %s", err)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// testRepo creates a repository with a module whose only file has the given
+// contents in successive commits, tagged v1.0.0, v1.1.0 and so on.
+func testRepo(t *testing.T, sources ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{"go.mod": "module example.com/m\n\ngo 1.26\n"}
+	for i, src := range sources {
+		files["m.go"] = "package m\n\n" + src + "\n"
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := wt.Add(name); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sig := &object.Signature{
+			Name:  "test",
+			Email: "test@example.com",
+			When:  time.Date(2020, 1, 1, 0, 0, i, 0, time.UTC),
+		}
+		h, err := wt.Commit("commit", &git.CommitOptions{Author: sig, Committer: sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name := plumbing.NewTagReferenceName(fmt.Sprintf("v1.%d.0", i))
+		if err := r.Storer.SetReference(plumbing.NewHashReference(name, h)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func serveDiff(t *testing.T, h diffHandler, ctx context.Context, method, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "/diff", strings.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestDiffHandler(t *testing.T) {
+	dir := testRepo(t, "func F() {}", "func F(a int) {}")
+	body := func(from, to string) string {
+		b, err := json.Marshal(diffRequest{Path: dir, From: from, To: to})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	testCases := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		err    string
+	}{
+		{"method", http.MethodGet, "", http.StatusMethodNotAllowed, "only POST is allowed"},
+		{"invalid body", http.MethodPost, "{", http.StatusBadRequest, "invalid request"},
+		{"missing ref", http.MethodPost, `{"path":"` + dir + `","from":"v1.0.0"}`, http.StatusBadRequest, "path, from and to are required"},
+		{"unknown ref", http.MethodPost, body("v1.0.0", "v9.0.0"), http.StatusUnprocessableEntity, "v9.0.0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveDiff(t, diffHandler{}, context.Background(), tc.method, tc.body)
+			if rec.Code != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, rec.Code)
+			}
+
+			var resp errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(resp.Error, tc.err) {
+				t.Errorf("expected error containing %q, got %q", tc.err, resp.Error)
+			}
+		})
+	}
+
+	t.Run("diff", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		for i := 0; i < 2; i++ {
+			rec := serveDiff(t, diffHandler{cacheDir: cacheDir}, context.Background(), http.MethodPost, body("v1.0.0", "v1.1.0"))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+			}
+
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("expected JSON content type, got %q", ct)
+			}
+
+			var resp diffResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.Recommend != "major" {
+				t.Errorf("expected a major bump, got %q", resp.Recommend)
+			}
+
+			var changes []string
+			for _, p := range resp.Packages {
+				for _, c := range p.Changes {
+					changes = append(changes, p.Path+": "+c.Change)
+				}
+			}
+
+			want := []string{"example.com/m: function F: argument a with type int at position 0: was added"}
+			if !reflect.DeepEqual(changes, want) {
+				t.Errorf("expected changes %q, got %q", want, changes)
+			}
+
+			cached, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(cached) != 1 {
+				t.Errorf("expected the diff to be cached once, got %v", cached)
+			}
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		rec := serveDiff(t, diffHandler{}, ctx, http.MethodPost, body("v1.0.0", "v1.1.0"))
+		if rec.Body.Len() > 0 {
+			t.Errorf("expected no response for a cancelled request, got %s", rec.Body)
+		}
+	})
+}
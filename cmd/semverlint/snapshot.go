@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/erizocosmico/semverlint"
+)
+
+// snapshot writes the API of the project to the standard output, so it can
+// be used later as the baseline of check.
+func snapshot(args []string) error {
+	flags := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	path := flags.String("path", ".", "path of the project")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	api, err := semverlint.ProjectAPIWithOptions(*path, loadOptions)
+	if err != nil {
+		return fmt.Errorf("unable to get API: %s", err)
+	}
+
+	return semverlint.WriteAPI(os.Stdout, api)
+}
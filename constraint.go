@@ -0,0 +1,344 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// TypeParamChanged is reported when a type parameter of a generic function
+// changes.
+type TypeParamChanged struct {
+	Pos     int
+	Name    string
+	Changes []Change
+}
+
+func (t TypeParamChanged) String() string {
+	return fmt.Sprintf(
+		"type parameter %s at position %d: %s",
+		t.Name,
+		t.Pos,
+		joinChanges(t.Changes),
+	)
+}
+
+// ConstraintNarrowed is reported when the constraint of a type parameter
+// accepts fewer type arguments than before, which breaks the callers
+// instantiating it with the types that are no longer accepted.
+type ConstraintNarrowed struct {
+	From types.Type
+	To   types.Type
+}
+
+func (c ConstraintNarrowed) String() string {
+	return fmt.Sprintf("constraint narrowed from %q to %q", c.From, c.To)
+}
+
+// ConstraintWidened is reported when the constraint of a type parameter
+// accepts all the type arguments it accepted before and more.
+type ConstraintWidened struct {
+	From types.Type
+	To   types.Type
+}
+
+func (c ConstraintWidened) String() string {
+	return fmt.Sprintf("constraint widened from %q to %q", c.From, c.To)
+}
+
+// TypeArgumentChanged is reported when a type argument of an instantiation
+// of a generic type changes, e.g. Result[string] becoming Result[int].
+type TypeArgumentChanged struct {
+	// Type is the name of the generic type.
+	Type string
+	Pos  int
+	From types.Type
+	To   types.Type
+}
+
+func (t TypeArgumentChanged) String() string {
+	return fmt.Sprintf(
+		"type argument %d of %s changed from %q to %q",
+		t.Pos,
+		t.Type,
+		typeString(t.From),
+		typeString(t.To),
+	)
+}
+
+// ArityChanged is reported when the number of type parameters of a generic
+// function or type changes, which breaks all its instantiations. Type is
+// the name of the generic type when it's reported for a type instantiating
+// it, e.g. Cache[string, int] becoming Cache[int].
+type ArityChanged struct {
+	Type string
+	From int
+	To   int
+}
+
+func (a ArityChanged) String() string {
+	if a.Type == "" {
+		return fmt.Sprintf("number of type parameters changed from %d to %d", a.From, a.To)
+	}
+	return fmt.Sprintf("number of type parameters of %s changed from %d to %d", a.Type, a.From, a.To)
+}
+
+// TypeParamsReordered is reported when the type parameters of a generic
+// function or type are reordered, e.g. F[K, V] becoming F[V, K], which
+// breaks the instantiations with explicit type arguments and may break the
+// inference of the type arguments.
+type TypeParamsReordered struct {
+	From []string
+	To   []string
+}
+
+func (t TypeParamsReordered) String() string {
+	return fmt.Sprintf(
+		"type parameters reordered from [%s] to [%s]",
+		strings.Join(t.From, ", "),
+		strings.Join(t.To, ", "),
+	)
+}
+
+// typeParamsReordered returns a TypeParamsReordered change if the given type
+// parameters have the same names in a different order.
+func typeParamsReordered(prev, current []TypeParam) (Change, bool) {
+	if len(prev) != len(current) {
+		return nil, false
+	}
+
+	var from, to = make([]string, len(prev)), make([]string, len(current))
+	var positions = make(map[string]int, len(prev))
+	for i, p := range prev {
+		from[i] = p.Name
+		positions[p.Name] = i
+	}
+
+	var moved bool
+	for i, c := range current {
+		to[i] = c.Name
+		j, ok := positions[c.Name]
+		if !ok {
+			return nil, false
+		}
+		moved = moved || i != j
+	}
+
+	if !moved {
+		return nil, false
+	}
+	return TypeParamsReordered{from, to}, true
+}
+
+// withoutReorderedTypes removes from the given changes of the arguments,
+// results or fields of a generic function or type whose type parameters
+// were reordered the type changes caused only by the reordering, e.g. an
+// argument of type K, which is represented by the position of K. They are
+// explained by the TypeParamsReordered change.
+func withoutReorderedTypes(changes []Change, opts DiffOptions) []Change {
+	reordered := func(cs []Change) bool {
+		if len(cs) != 1 {
+			return false
+		}
+
+		tc, ok := cs[0].(TypeChanged)
+		if !ok || isSerialized(tc.From) || isSerialized(tc.To) {
+			return false
+		}
+		return types.TypeString(unalias(tc.From), opts.qualifier()) == types.TypeString(unalias(tc.To), nil)
+	}
+
+	var result []Change
+	for _, c := range changes {
+		switch c := c.(type) {
+		case ArgumentChanged:
+			if reordered(c.Changes) {
+				continue
+			}
+		case ResultChanged:
+			if reordered(c.Changes) {
+				continue
+			}
+		case FieldChanged:
+			if reordered(c.Changes) {
+				continue
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// typeArgsDiff returns the changes in the type arguments of two
+// instantiations of the same generic type, or pointers to them, or nil if
+// they are not.
+func typeArgsDiff(prev, current types.Type, opts DiffOptions) []Change {
+	p1, ok1 := types.Unalias(prev).(*types.Pointer)
+	p2, ok2 := types.Unalias(current).(*types.Pointer)
+	if ok1 && ok2 {
+		prev, current = p1.Elem(), p2.Elem()
+	}
+
+	n1, ok1 := types.Unalias(prev).(*types.Named)
+	n2, ok2 := types.Unalias(current).(*types.Named)
+	if !ok1 || !ok2 || n1.TypeArgs().Len() == 0 && n2.TypeArgs().Len() == 0 {
+		return nil
+	}
+
+	// Instantiations with a different number of type arguments are only
+	// of the same generic type if it has the same name and package.
+	if n1.TypeArgs().Len() != n2.TypeArgs().Len() {
+		if n1.Obj().Pkg() == nil || n2.Obj().Pkg() == nil ||
+			opts.modulePath(n1.Obj().Pkg().Path()) != n2.Obj().Pkg().Path() ||
+			n1.Obj().Name() != n2.Obj().Name() {
+			return nil
+		}
+		return []Change{ArityChanged{n2.Obj().Name(), n1.TypeArgs().Len(), n2.TypeArgs().Len()}}
+	}
+
+	if typeKey(n1.Origin(), opts.qualifier()) != typeKey(n2.Origin(), nil) {
+		return nil
+	}
+
+	var changes []Change
+	for i := 0; i < n1.TypeArgs().Len(); i++ {
+		a1, a2 := n1.TypeArgs().At(i), n2.TypeArgs().At(i)
+		if !typesEqual(a1, a2, opts) {
+			changes = append(changes, TypeArgumentChanged{n2.Obj().Name(), i, a1, a2})
+		}
+	}
+	return changes
+}
+
+// typeParamsDiff returns the changes in the number and constraints of the
+// type parameters of a function or type. If their number or order
+// changed, the parameters at the same position are not necessarily the
+// same one, so their constraints are not compared.
+func typeParamsDiff(prev, current []TypeParam, opts DiffOptions) []Change {
+	if len(prev) != len(current) {
+		return []Change{ArityChanged{From: len(prev), To: len(current)}}
+	}
+
+	// The constraints of reordered type parameters can't be compared by
+	// position either.
+	if c, ok := typeParamsReordered(prev, current); ok {
+		return []Change{c}
+	}
+
+	var changes []Change
+	for i := range prev {
+		p, c := prev[i], current[i]
+		if typesEqual(p.Constraint, c.Constraint, opts) {
+			continue
+		}
+
+		changes = append(changes, TypeParamChanged{i, c.Name, []Change{
+			constraintChange(p.Constraint, c.Constraint, opts),
+		}})
+	}
+	return changes
+}
+
+// constraintChange returns the change between two different constraints.
+// The constraints are compared by the methods they require, whether they
+// require comparable types and the types they allow. They are narrowed if
+// any of these are more restrictive than before and none less, and widened
+// in the opposite case. Otherwise, they're just different.
+func constraintChange(prev, current types.Type, opts DiffOptions) Change {
+	pi, ok1 := prev.Underlying().(*types.Interface)
+	ci, ok2 := current.Underlying().(*types.Interface)
+	if !ok1 || !ok2 {
+		return TypeChanged{From: prev, To: current}
+	}
+
+	var narrowed, widened bool
+	compare := func(prevHas, currentHas bool) {
+		if currentHas && !prevHas {
+			narrowed = true
+		} else if prevHas && !currentHas {
+			widened = true
+		}
+	}
+
+	pm, cm := constraintMethods(pi, opts.qualifier()), constraintMethods(ci, nil)
+	for m := range cm {
+		compare(pm[m], true)
+	}
+	for m := range pm {
+		compare(true, cm[m])
+	}
+
+	compare(pi.IsComparable(), ci.IsComparable())
+
+	pt, pRestricted := constraintTerms(pi, opts.qualifier())
+	ct, cRestricted := constraintTerms(ci, nil)
+	compare(pRestricted, cRestricted)
+	if pRestricted && cRestricted {
+		for t := range ct {
+			if !pt[t] {
+				widened = true
+			}
+		}
+		// A type is still allowed if its underlying type is allowed,
+		// e.g. int when it becomes ~int.
+		for t := range pt {
+			if !ct[t] && !ct["~"+t] {
+				narrowed = true
+			}
+		}
+	}
+
+	switch {
+	case narrowed && !widened:
+		return ConstraintNarrowed{From: prev, To: current}
+	case widened && !narrowed:
+		return ConstraintWidened{From: prev, To: current}
+	default:
+		return TypeChanged{From: prev, To: current}
+	}
+}
+
+// constraintMethods returns the keys of the methods required by the
+// constraint, made of their names and signatures.
+func constraintMethods(iface *types.Interface, qualifier types.Qualifier) map[string]bool {
+	var result = make(map[string]bool)
+	for i := 0; i < iface.NumMethods(); i++ {
+		m := iface.Method(i)
+		result[m.Name()+typeKey(m.Type(), qualifier)] = true
+	}
+	return result
+}
+
+// constraintTerms returns the keys of the types allowed by the constraint
+// and whether it restricts them at all. All the types allowed by embedded
+// elements are considered allowed, which is accurate for the usual case of
+// a single union of types.
+func constraintTerms(iface *types.Interface, qualifier types.Qualifier) (map[string]bool, bool) {
+	var terms = make(map[string]bool)
+	var restricted bool
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		switch e := iface.EmbeddedType(i); u := e.Underlying().(type) {
+		case *types.Union:
+			restricted = true
+			for j := 0; j < u.Len(); j++ {
+				key := typeKey(u.Term(j).Type(), qualifier)
+				if u.Term(j).Tilde() {
+					key = "~" + key
+				}
+				terms[key] = true
+			}
+		case *types.Interface:
+			embedded, ok := constraintTerms(u, qualifier)
+			if ok {
+				restricted = true
+				for t := range embedded {
+					terms[t] = true
+				}
+			}
+		default:
+			restricted = true
+			terms[typeKey(e, qualifier)] = true
+		}
+	}
+	return terms, restricted
+}
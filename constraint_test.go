@@ -0,0 +1,158 @@
+package semverlint
+
+import "testing"
+
+func TestConstraintChanged(t *testing.T) {
+	testCases := []struct {
+		name       string
+		prev, curr string
+		want       []string
+		major      bool
+	}{
+		{
+			"narrowed",
+			"func F[T any](x T) {}",
+			"func F[T comparable](x T) {}",
+			[]string{`example.com/m: function F: type parameter T at position 0: constraint narrowed from "any" to "comparable"`},
+			true,
+		},
+		{
+			"widened",
+			"func F[T comparable](x T) {}",
+			"func F[T any](x T) {}",
+			[]string{`example.com/m: function F: type parameter T at position 0: constraint widened from "comparable" to "any"`},
+			false,
+		},
+		{
+			"union narrowed",
+			"func F[T int | int64 | string](x T) {}",
+			"func F[T int | int64](x T) {}",
+			[]string{`example.com/m: function F: type parameter T at position 0: constraint narrowed from "int | int64 | string" to "int | int64"`},
+			true,
+		},
+		{
+			"union widened",
+			"func F[T int | int64](x T) {}",
+			"func F[T ~int | int64 | string](x T) {}",
+			[]string{`example.com/m: function F: type parameter T at position 0: constraint widened from "int | int64" to "~int | int64 | string"`},
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := diffSources(t, tc.prev, tc.curr)
+			AssertChanges(t, changes, tc.want)
+
+			if major := Recommend(changes) == MajorBump; major != tc.major {
+				t.Errorf("expected major bump to be %v, got %s", tc.major, Recommend(changes))
+			}
+		})
+	}
+}
+
+func TestTypeArgumentChanged(t *testing.T) {
+	decls := `
+type Result[T any] struct{ Value T }
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+`
+
+	prev := decls + `
+func Get(r Result[string]) {}
+
+func Load() (Pair[string, int], error) { return Pair[string, int]{}, nil }
+
+func Same(r Result[int]) {}
+`
+	current := decls + `
+func Get(r Result[int]) {}
+
+func Load() (Pair[string, []byte], error) { return Pair[string, []byte]{}, nil }
+
+func Same(r Result[int]) {}
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: function Get: argument r with type example.com/m.Result[int] at position 0: type argument 0 of Result changed from "string" to "int"`,
+		`example.com/m: function Load: result with type example.com/m.Pair[string, []byte] at position 0: type argument 1 of Pair changed from "int" to "[]byte"`,
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+}
+
+func TestArityChanged(t *testing.T) {
+	prev := `
+type Cache[K comparable, V any] struct{ m map[K]V }
+
+func New() *Cache[string, int] { return nil }
+
+func Get(c *Cache[string, int], key string) int { return 0 }
+
+func Keys[K comparable, V any](m map[K]V) []K { return nil }
+
+func Len(m map[string]int) int { return 0 }
+`
+	current := `
+type Cache[V any] struct{ m map[string]V }
+
+func New() *Cache[int] { return nil }
+
+func Get(c *Cache[int], key string) int { return 0 }
+
+func Keys[K comparable](m map[K]int) []K { return nil }
+
+func Len(m map[string]int) int { return 0 }
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: function Get: argument c with type *example.com/m.Cache[int] at position 0: number of type parameters of Cache changed from 2 to 1`,
+		`example.com/m: function Keys: number of type parameters changed from 2 to 1, argument m with type map[K]int at position 0: type changed from "map[K]V" to "map[K]int"`,
+		`example.com/m: function New: result with type *example.com/m.Cache[int] at position 0: number of type parameters of Cache changed from 2 to 1`,
+		`example.com/m: struct Cache: number of type parameters changed from 2 to 1`,
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+}
+
+func TestTypeParamsReordered(t *testing.T) {
+	prev := `
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func Map[K comparable, V any](m map[K]V) Pair[K, V] { return Pair[K, V]{} }
+
+func Same[K comparable, V any](k K, v V) {}
+`
+	current := `
+type Pair[V any, K comparable] struct {
+	Key   K
+	Value V
+}
+
+func Map[V any, K comparable](m map[K]V) Pair[V, K] { return Pair[V, K]{} }
+
+func Same[K comparable, V any](k K, v V) {}
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: function Map: type parameters reordered from [K, V] to [V, K]`,
+		`example.com/m: struct Pair: type parameters reordered from [K, V] to [V, K]`,
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+}
@@ -1,32 +1,150 @@
 package semverlint
 
-import "go/types"
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+)
 
-// Diff computes the difference between two given public APIs.
+// DiffOptions customise how two public APIs are compared.
+type DiffOptions struct {
+	// StrictFieldAdditions reports fields added to exported structs as
+	// breaking changes, because they break composite literals of the struct
+	// that don't use field names.
+	StrictFieldAdditions bool
+
+	// ReportCosmetic reports changes that don't affect compatibility at all,
+	// such as renamed parameters, so they can be mentioned in release notes.
+	ReportCosmetic bool
+
+	// RenameThreshold is the maximum edit distance between the names of a
+	// removed and an added declaration of the same kind and shape for them
+	// to be reported as a rename. Rename detection is disabled if it's 0.
+	RenameThreshold int
+
+	// StdInterfaces reports the structs and interfaces that no longer
+	// implement well-known interfaces of the standard library, such as
+	// io.Reader, because of changes in their methods.
+	StdInterfaces bool
+
+	// StdShadowing annotates the added exported declarations named after
+	// well-known types of the standard library, such as Context or Reader,
+	// which users may confuse with them. It's advisory, the annotations
+	// are cosmetic.
+	StdShadowing bool
+
+	// DetectFunctionalOptions annotates the changes of functions whose
+	// arguments were collapsed into variadic functional options, e.g.
+	// F(addr string, timeout time.Duration) becoming F(opts ...Option),
+	// so the refactor is easier to recognize. The changes are still
+	// breaking.
+	DetectFunctionalOptions bool
+
+	// TagOptionSeverity is the severity of the changes of known options
+	// in the tags of struct fields, such as omitempty and required, which
+	// can change the behavior of the code encoding, decoding or validating
+	// the struct at runtime. They are not reported if it's Cosmetic and
+	// cosmetic changes are not reported.
+	TagOptionSeverity Severity
+
+	// StrictAliases reports types replaced by aliases of them or the other
+	// way around, e.g. an argument of type B becoming of type A after
+	// declaring type A = B, and aliases retargeted to aliases of the same
+	// type. They are identical types, so they are not reported otherwise.
+	StrictAliases bool
+
+	// StrictAliasSeverity is the severity of the changes reported by
+	// StrictAliases. They are not reported if it's Cosmetic and cosmetic
+	// changes are not reported.
+	StrictAliasSeverity Severity
+
+	// ModulePaths maps module paths in the previous API to the module paths
+	// they have in the current one, e.g. github.com/me/mod to
+	// github.com/me/mod/v2 after a major version bump, so that packages
+	// and types are compared with their counterparts in the new module.
+	ModulePaths map[string]string
+
+	// ExcludeSymbols are the declarations and methods whose changes are
+	// dropped, as accepted by the ExcludeSymbols transformer, e.g.
+	// github.com/me/mod/pkg.Foo or github.com/me/mod/pkg.Foo.Bar. They are
+	// excluded before applying the transformers.
+	ExcludeSymbols []string
+
+	// Transformers are applied in order to the changes before returning
+	// them.
+	Transformers []Transformer
+}
+
+// modulePath returns the path that the given package path of the previous
+// API has in the current one. If several module paths match, the longest
+// one is used, since it's the module the package belongs to.
+func (o DiffOptions) modulePath(path string) string {
+	var module string
+	for from := range o.ModulePaths {
+		if len(from) > len(module) && (path == from || strings.HasPrefix(path, from+"/")) {
+			module = from
+		}
+	}
+
+	if module == "" {
+		return path
+	}
+	return o.ModulePaths[module] + path[len(module):]
+}
+
+// qualifier returns the qualifier used to compute the keys of types of the
+// previous API, or nil if no module paths are mapped.
+func (o DiffOptions) qualifier() types.Qualifier {
+	if len(o.ModulePaths) == 0 {
+		return nil
+	}
+
+	return func(p *types.Package) string {
+		return o.modulePath(p.Path())
+	}
+}
+
+// Diff computes the difference between two given public APIs. The previous
+// API can be empty, e.g. for projects that were not released yet, in which
+// case every package of the current one is added. See InitialVersion.
 func Diff(current, prev API) APIChanges {
+	return DiffWithOptions(current, prev, DiffOptions{})
+}
+
+// DiffWithOptions computes the difference between two given public APIs
+// using the given options.
+func DiffWithOptions(current, prev API, opts DiffOptions) APIChanges {
 	var changes APIChanges
 	currentPkgs := packagesIndex(current)
-	prevPkgs := packagesIndex(prev)
 
 	var seen = make(map[string]struct{})
-	for path, p1 := range prevPkgs {
+	for _, p1 := range prev {
+		path := opts.modulePath(p1.Path)
 		seen[path] = struct{}{}
 		p2, ok := currentPkgs[path]
 		if !ok {
+			var pc = []Change{Removed{}}
+			if isDeprecated(p1.Doc) {
+				pc = append(pc, WasDeprecated{})
+			}
 			changes = append(changes, NewPackageChanges(
 				p1.Name, p1.Path,
-				NewDeclChange(p1.Name, PackageType, Removed{}),
+				NewDeclChange(p1.Name, PackageType, pc...),
 			))
 			continue
 		}
 
-		changes = append(changes, packageDiff(p2, p1))
+		changes = append(changes, packageDiff(p1, p2, opts))
 	}
 
 	// Add the packages that were not present as new.
-	for path, p := range currentPkgs {
+	for _, p := range current {
 		// Skip packages we've already seen.
-		if _, ok := seen[path]; !ok {
+		if _, ok := seen[p.Path]; !ok {
 			changes = append(changes, NewPackageChanges(
 				p.Name, p.Path,
 				NewDeclChange(p.Name, PackageType, Added{}),
@@ -34,17 +152,75 @@ func Diff(current, prev API) APIChanges {
 		}
 	}
 
+	changes = aliasTargetChanges(changes, current, prev, opts)
+	if len(opts.ExcludeSymbols) > 0 {
+		changes = ExcludeSymbols(opts.ExcludeSymbols...)(changes)
+	}
+
+	for _, t := range opts.Transformers {
+		changes = t(changes)
+	}
+
 	return changes
 }
 
-func packageDiff(prev, current Package) PackageChanges {
+// IsCompatible reports whether the current API is backwards compatible with
+// the previous one, that is, there are no breaking changes between them.
+func IsCompatible(prev, current API) bool {
+	for _, pkg := range Diff(current, prev) {
+		for _, c := range pkg.Changes {
+			if IsBreaking(c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// DiffFiltered computes the difference between two given public APIs, only
+// taking into account the packages whose import path is in the include list.
+func DiffFiltered(current, prev API, include []string) APIChanges {
+	return Diff(filterPackages(current, include), filterPackages(prev, include))
+}
+
+func filterPackages(api API, include []string) API {
+	var paths = make(map[string]struct{}, len(include))
+	for _, p := range include {
+		paths[p] = struct{}{}
+	}
+
+	var result API
+	for _, p := range api {
+		if _, ok := paths[p.Path]; ok {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func packageDiff(prev, current Package, opts DiffOptions) PackageChanges {
 	var changes []Change
-	changes = append(changes, constsDiff(prev.Consts, current.Consts)...)
-	changes = append(changes, varsDiff(prev.Vars, current.Vars)...)
-	changes = append(changes, funcsDiff(prev.Funcs, current.Funcs)...)
-	changes = append(changes, structsDiff(prev.Structs, current.Structs)...)
-	changes = append(changes, interfacesDiff(prev.Interfaces, current.Interfaces)...)
-	changes = append(changes, typesDiff(prev.Types, current.Types)...)
+	if prev.Name != current.Name {
+		changes = append(changes, NewDeclChange(current.Name, PackageType, NameChanged{
+			From: prev.Name,
+			To:   current.Name,
+		}))
+	}
+
+	changes = append(changes, constsDiff(prev.Consts, current.Consts, opts)...)
+	changes = append(changes, varsDiff(prev.Vars, current.Vars, opts)...)
+	changes = append(changes, funcsDiff(prev.Funcs, current.Funcs, opts)...)
+	changes = append(changes, structsDiff(prev, current, opts)...)
+	changes = append(changes, interfacesDiff(prev.Interfaces, current.Interfaces, opts)...)
+	changes = append(changes, typesDiff(prev, current, opts)...)
+	changes = kindChanges(changes)
+	if opts.RenameThreshold > 0 {
+		changes = renames(prev, current, changes, opts)
+	}
+	if opts.StdShadowing {
+		changes = stdShadowing(changes)
+	}
+	changes = withSignatures(prev, current, changes)
 	return PackageChanges{
 		Path:    current.Path,
 		Name:    current.Name,
@@ -52,35 +228,98 @@ func packageDiff(prev, current Package) PackageChanges {
 	}
 }
 
-func constsDiff(prev, current []Const) []Change {
+// kindChanges merges the removal and addition of declarations with the same
+// name but different kind, e.g. a variable replaced by a function, into a
+// single KindChanged change.
+func kindChanges(changes []Change) []Change {
+	var added = make(map[string]DeclType)
+	for _, c := range changes {
+		if d, ok := c.(DeclChange); ok && isDeclChange(d, Added{}) {
+			added[d.Name] = d.Type
+		}
+	}
+
+	var merged = make(map[string]struct{})
+	var result = make([]Change, 0, len(changes))
+	for _, c := range changes {
+		if d, ok := c.(DeclChange); ok && isDeclChange(d, Removed{}) {
+			if typ, ok := added[d.Name]; ok && typ != d.Type {
+				merged[d.Name] = struct{}{}
+				result = append(result, NewDeclChange(d.Name, d.Type, KindChanged{
+					From: d.Type,
+					To:   typ,
+				}))
+				continue
+			}
+		}
+		result = append(result, c)
+	}
+
+	if len(merged) == 0 {
+		return changes
+	}
+
+	var filtered = result[:0]
+	for _, c := range result {
+		if d, ok := c.(DeclChange); ok && isDeclChange(d, Added{}) {
+			if _, ok := merged[d.Name]; ok {
+				continue
+			}
+		}
+		filtered = append(filtered, c)
+	}
+
+	return filtered
+}
+
+// isDeclChange reports whether the declaration change consists of the given
+// change, maybe followed by details about it, such as EnumValueRemoved.
+func isDeclChange(d DeclChange, c Change) bool {
+	return len(d.Changes) > 0 && d.Changes[0] == c
+}
+
+func constsDiff(prev, current []Const, opts DiffOptions) []Change {
 	var changes []Change
 	currentConsts := constsIndex(current)
-	prevConsts := constsIndex(prev)
+	shifts := iotaShifts(prev, currentConsts, opts)
+	enums := enumTypes(prev)
 
 	var seen = make(map[string]struct{})
-	for name, v := range prevConsts {
+	for _, v := range prev {
+		name := v.Name
 		seen[name] = struct{}{}
 		v2, ok := currentConsts[name]
 		if !ok {
-			changes = append(changes, NewDeclChange(name, ConstType, Removed{}))
+			var rc = []Change{Removed{}}
+			if enum, ok := enums[typeKey(v.Type, nil)]; ok {
+				rc = append(rc, EnumValueRemoved{enum})
+			}
+			changes = append(changes, NewDeclChange(name, ConstType, rc...))
+			continue
+		}
+
+		if dc := docDiff(v.Doc, v2.Doc, opts); len(dc) > 0 {
+			changes = append(changes, NewDeclChange(name, ConstType, dc...))
 		}
 
-		if !typesEqual(v.Type, v2.Type) {
+		if !typesEqual(v.Type, v2.Type, opts) {
 			changes = append(changes, NewDeclChange(name, ConstType, TypeChanged{
 				From: v.Type,
 				To:   v2.Type,
 			}))
 		}
 
-		if v.Value != v2.Value {
-			changes = append(changes, NewDeclChange(name, ConstType, ValueChanged{
-				From: v.Value,
-				To:   v2.Value,
-			}))
+		if !constValuesEqual(v.Value, v2.Value) {
+			vc := []Change{ValueChanged{From: v.Value, To: v2.Value}}
+			if delta, ok := shifts[name]; ok {
+				vc = append(vc, IotaShifted{delta})
+			}
+			changes = append(changes, NewDeclChange(name, ConstType, vc...))
 		}
 	}
 
-	for name := range currentConsts {
+	for _, v := range current {
+		name := v.Name
 		if _, ok := seen[name]; !ok {
 			changes = append(changes, NewDeclChange(name, ConstType, Added{}))
 		}
@@ -89,26 +328,150 @@ func constsDiff(prev, current []Const) []Change {
 	return changes
 }
 
-func varsDiff(prev, current []Var) []Change {
+// constValuesEqual reports whether two values of constants, as written by
+// constant.Value.ExactString, are numerically equal, even if they are
+// written differently, e.g. 1.5 and 3/2. Values that can't be parsed as
+// numbers are compared as they are.
+func constValuesEqual(prev, current string) bool {
+	if prev == current {
+		return true
+	}
+
+	x, ok1 := parseConstValue(prev)
+	y, ok2 := parseConstValue(current)
+	if !ok1 || !ok2 {
+		return false
+	}
+	return constant.Compare(x, token.EQL, y)
+}
+
+// parseConstValue parses a numeric value written by
+// constant.Value.ExactString, which writes fractions as quotients and
+// negative numbers with a sign, which literals don't have.
+func parseConstValue(s string) (constant.Value, bool) {
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		v, ok := parseConstValue(rest)
+		if !ok {
+			return nil, false
+		}
+		return constant.UnaryOp(token.SUB, v, 0), true
+	}
+
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		x, ok1 := parseConstValue(num)
+		y, ok2 := parseConstValue(den)
+		if !ok1 || !ok2 || constant.Sign(y) == 0 {
+			return nil, false
+		}
+		return constant.BinaryOp(x, token.QUO, y), true
+	}
+
+	for _, tok := range []token.Token{token.INT, token.FLOAT} {
+		if v := constant.MakeFromLiteral(s, tok, 0); v.Kind() != constant.Unknown {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// enumTypes returns the names of the named types with several constants,
+// which are used as enums, by the key of the type.
+func enumTypes(consts []Const) map[string]string {
+	var count = make(map[string]int)
+	for _, c := range consts {
+		count[typeKey(c.Type, nil)]++
+	}
+
+	var result = make(map[string]string)
+	for _, c := range consts {
+		name, ok := namedPath(c.Type)
+		if key := typeKey(c.Type, nil); ok && count[key] > 1 {
+			result[key] = name[strings.LastIndex(name, ".")+1:]
+		}
+	}
+	return result
+}
+
+// iotaShifts returns the integer constants whose values were shifted by the
+// same amount as other constants of the same type, which usually means a
+// constant was inserted or removed in the middle of an iota block, along
+// with the amount they were shifted by.
+func iotaShifts(prev []Const, current map[string]Const, opts DiffOptions) map[string]int64 {
+	type shift struct {
+		typ   string
+		delta int64
+	}
+
+	var groups = make(map[shift][]string)
+	for _, v := range prev {
+		v2, ok := current[v.Name]
+		if !ok || constValuesEqual(v.Value, v2.Value) || !typesEqual(v.Type, v2.Type, opts) {
+			continue
+		}
+
+		from, err := strconv.ParseInt(v.Value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		to, err := strconv.ParseInt(v2.Value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		s := shift{typeKey(v2.Type, nil), to - from}
+		groups[s] = append(groups[s], v.Name)
+	}
+
+	var result = make(map[string]int64)
+	for s, names := range groups {
+		if len(names) < 2 {
+			continue
+		}
+
+		for _, n := range names {
+			result[n] = s.delta
+		}
+	}
+	return result
+}
+
+func varsDiff(prev, current []Var, opts DiffOptions) []Change {
 	var changes []Change
 	currentVars := varsIndex(current)
-	prevVars := varsIndex(prev)
 
 	var seen = make(map[string]struct{})
-	for name, v := range prevVars {
+	for _, v := range prev {
+		name := v.Name
 		seen[name] = struct{}{}
 		v2, ok := currentVars[name]
 		if !ok {
-			changes = append(changes, NewDeclChange(name, VarType, Removed{}))
-		} else if !typesEqual(v.Type, v2.Type) {
-			changes = append(changes, NewDeclChange(name, VarType, TypeChanged{
-				From: v.Type,
-				To:   v2.Type,
-			}))
+			var rc = []Change{Removed{}}
+			if isSentinelError(v) {
+				rc = append(rc, SentinelErrorRemoved{})
+			}
+			changes = append(changes, NewDeclChange(name, VarType, rc...))
+			continue
+		}
+
+		if dc := docDiff(v.Doc, v2.Doc, opts); len(dc) > 0 {
+			changes = append(changes, NewDeclChange(name, VarType, dc...))
+		}
+
+		if !typesEqual(v.Type, v2.Type, opts) {
+			var tc Change = TypeChanged{From: v.Type, To: v2.Type}
+			// Types read back from JSON can't be inspected.
+			if (isChan(v.Type) || isChan(v2.Type)) && !isSerialized(v.Type) && !isSerialized(v2.Type) {
+				tc = newChannelChanged(v.Type, v2.Type)
+			}
+			changes = append(changes, NewDeclChange(name, VarType, tc))
+		} else if ac := aliasSubstitution(v.Type, v2.Type, opts); len(ac) > 0 {
+			changes = append(changes, NewDeclChange(name, VarType, ac...))
 		}
 	}
 
-	for name := range currentVars {
+	for _, v := range current {
+		name := v.Name
 		if _, ok := seen[name]; !ok {
 			changes = append(changes, NewDeclChange(name, VarType, Added{}))
 		}
@@ -117,28 +480,45 @@ func varsDiff(prev, current []Var) []Change {
 	return changes
 }
 
-func funcsDiff(prev, current []Func) []Change {
+func funcsDiff(prev, current []Func, opts DiffOptions) []Change {
 	var changes []Change
 	currentFuncs := funcsIndex(current)
-	prevFuncs := funcsIndex(prev)
 
 	var seen = make(map[string]struct{})
-	for name, v := range prevFuncs {
+	for _, v := range prev {
+		name := v.Name
 		seen[name] = struct{}{}
 		v2, ok := currentFuncs[name]
 		if !ok {
 			changes = append(changes, NewDeclChange(name, FuncType, Removed{}))
+			continue
+		}
+
+		if dc := docDiff(v.Doc, v2.Doc, opts); len(dc) > 0 {
+			changes = append(changes, NewDeclChange(name, FuncType, dc...))
 		}
 
-		_ = v
-		_ = v2
+		fc := typeParamsDiff(v.TypeParams, v2.TypeParams, opts)
+		if _, ok := typeParamsReordered(v.TypeParams, v2.TypeParams); ok {
+			fc = append(fc, withoutReorderedTypes(funcDiff(v, v2, opts), opts)...)
+		} else {
+			fc = append(fc, funcDiff(v, v2, opts)...)
+		}
+		if opts.DetectFunctionalOptions && len(fc) > 0 {
+			if t, ok := functionalOptions(v, v2, opts); ok {
+				fc = append(fc, FunctionalOptionsRefactor{t})
+			}
+		}
 
-		// TODO: check args
+		fc = append(fc, panicsDiff(v.Panics, v2.Panics)...)
 
-		// TODO: check returns
+		if len(fc) > 0 {
+			changes = append(changes, NewDeclChange(name, FuncType, fc...))
+		}
 	}
 
-	for name := range currentFuncs {
+	for _, v := range current {
+		name := v.Name
 		if _, ok := seen[name]; !ok {
 			changes = append(changes, NewDeclChange(name, FuncType, Added{}))
 		}
@@ -147,29 +527,87 @@ func funcsDiff(prev, current []Func) []Change {
 	return changes
 }
 
-func structsDiff(prev, current []Struct) []Change {
+// structsDiff returns the changes between the structs of two packages. The
+// functions of the current package are used to find struct constructors, and
+// the interfaces of both packages to find the ones no longer implemented by
+// values of the structs.
+func structsDiff(prevPkg, currentPkg Package, opts DiffOptions) []Change {
 	var changes []Change
+	prev, current := prevPkg.Structs, currentPkg.Structs
 	currentStructs := structsIndex(current)
-	prevStructs := structsIndex(prev)
 
 	var seen = make(map[string]struct{})
-	for name, v := range prevStructs {
+	for _, v := range prev {
+		name := v.Name
 		seen[name] = struct{}{}
 		v2, ok := currentStructs[name]
 		if !ok {
-			changes = append(changes, NewDeclChange(name, StructType, Removed{}))
+			// Unexported types are only part of the API while they are
+			// reachable from exported declarations, which are the ones
+			// reported as changed when they stop being so.
+			if ast.IsExported(name) {
+				changes = append(changes, NewDeclChange(name, StructType, Removed{}))
+			}
+			continue
+		}
+
+		if dc := docDiff(v.Doc, v2.Doc, opts); len(dc) > 0 {
+			changes = append(changes, NewDeclChange(name, StructType, dc...))
 		}
 
-		_ = v
-		_ = v2
+		fieldOpts := opts
+		if !ast.IsExported(name) {
+			// Users can't write composite literals of unexported structs.
+			fieldOpts.StrictFieldAdditions = false
+		}
 
-		// TODO: check fields
+		fc := typeParamsDiff(v.TypeParams, v2.TypeParams, opts)
+		if _, ok := typeParamsReordered(v.TypeParams, v2.TypeParams); ok {
+			fc = append(fc, withoutReorderedTypes(fieldsDiff(v.Fields, v2.Fields, fieldOpts), opts)...)
+		} else {
+			fc = append(fc, fieldsDiff(v.Fields, v2.Fields, fieldOpts)...)
+		}
+		if ctor := constructorOf(name, currentPkg.Funcs); ctor != "" {
+			for i, c := range fc {
+				if f, ok := c.(FieldChanged); ok && len(f.Changes) > 0 && f.Changes[0] == (Added{}) {
+					f.Changes = append(f.Changes, ConstructorNote{ctor})
+					fc[i] = f
+				}
+			}
+		}
 
-		// TODO: check methods
+		fc = append(fc, promotedFieldsDiff(v, v2)...)
+		if prevOpaque, opaque := !hasExportedFields(v.Fields), !hasExportedFields(v2.Fields); prevOpaque != opaque {
+			fc = append(fc, OpacityChanged{Opaque: opaque})
+		}
+
+		if ast.IsExported(name) && !hasExportedFields(v2.Fields) {
+			if ctor := constructorOf(name, prevPkg.Funcs); ctor != "" && !creatable(currentPkg, name) {
+				fc = append(fc, ConstructorRemoved{ctor})
+			}
+		}
+		if len(fc) > 0 {
+			changes = append(changes, NewDeclChange(name, StructType, fc...))
+		}
+
+		if mc := methodsDiff(v.Methods, v2.Methods, opts, false); len(mc) > 0 {
+			changes = append(changes, NewDeclChange(name, StructType, mc...))
+		}
+
+		if vc := valueInterfacesLost(v.Methods, v2.Methods, prevPkg, currentPkg, opts); len(vc) > 0 {
+			changes = append(changes, NewDeclChange(name, StructType, vc...))
+		}
+
+		if opts.StdInterfaces {
+			if lc := lostInterfaces(v.Methods, v2.Methods); len(lc) > 0 {
+				changes = append(changes, NewDeclChange(name, StructType, lc...))
+			}
+		}
 	}
 
-	for name := range currentStructs {
-		if _, ok := seen[name]; !ok {
+	for _, v := range current {
+		name := v.Name
+		if _, ok := seen[name]; !ok && ast.IsExported(name) {
 			changes = append(changes, NewDeclChange(name, StructType, Added{}))
 		}
 	}
@@ -177,27 +615,262 @@ func structsDiff(prev, current []Struct) []Change {
 	return changes
 }
 
-func interfacesDiff(prev, current []Interface) []Change {
+// constructorOf returns the name of the exported constructor of the struct
+// with the given name, if any. Constructors are either named after the
+// struct, e.g. NewFoo, or just New and return the struct or a pointer to it.
+func constructorOf(name string, funcs []Func) string {
+	for _, f := range funcs {
+		if f.Name == "New"+name {
+			return f.Name
+		}
+
+		if f.Name == "New" && len(f.Return) > 0 {
+			t := f.Return[0].Type
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem()
+			}
+
+			if n, ok := t.(*types.Named); ok && n.Obj().Name() == name {
+				return f.Name
+			}
+		}
+	}
+	return ""
+}
+
+// creatable reports whether values of the struct with the given name can be
+// obtained from the package, that is, whether any of its functions or the
+// methods of other types return the struct or a pointer to it. Methods of
+// the struct itself need a value of it to be called.
+func creatable(pkg Package, name string) bool {
+	var funcs = pkg.Funcs
+	for _, s := range pkg.Structs {
+		if s.Name != name {
+			funcs = append(funcs[:len(funcs):len(funcs)], s.Methods...)
+		}
+	}
+
+	for _, t := range pkg.Types {
+		funcs = append(funcs[:len(funcs):len(funcs)], t.Methods...)
+	}
+
+	path := pkg.Path + "." + name
+	for _, f := range funcs {
+		for _, r := range f.Return {
+			t := r.Type
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem()
+			}
+
+			if n, ok := namedPath(t); ok && n == path {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasExportedFields reports whether any of the given fields is exported.
+func hasExportedFields(fields []Field) bool {
+	for _, f := range fields {
+		if ast.IsExported(f.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldsDiff(prev, current []Field, opts DiffOptions) []Change {
+	var changes []Change
+	currentFields := fieldsIndex(current)
+
+	var seen = make(map[string]struct{})
+	for i, f := range prev {
+		if !ast.IsExported(f.Name) {
+			continue
+		}
+
+		seen[f.Name] = struct{}{}
+		j, ok := currentFields[f.Name]
+		if !ok {
+			fc := []Change{Removed{}}
+			if name, ok := unexportedField(f.Name, current); ok {
+				fc = append(fc, Unexported{name})
+			}
+			changes = append(changes, FieldChanged{i, f.Name, fc})
+			continue
+		}
+
+		var fc []Change
+		f2 := current[j]
+		if !typesEqual(f.Type, f2.Type, opts) {
+			fc = append(fc, TypeChanged{From: f.Type, To: f2.Type})
+			// Users can still access the field, but they can't name its
+			// type anymore.
+			fc = append(fc, unexportedTypeChanges(f.Type, f2.Type)...)
+		} else {
+			fc = append(fc, aliasSubstitution(f.Type, f2.Type, opts)...)
+		}
+
+		// Users can only build structs with unexported fields with keyed
+		// literals, so the order of their fields doesn't matter to them.
+		if keyed := hasUnexportedFields(prev); i != j && (!keyed || opts.ReportCosmetic) {
+			fc = append(fc, PositionChanged{From: i, To: j, Keyed: keyed})
+		}
+
+		if opts.TagOptionSeverity > Cosmetic || opts.ReportCosmetic {
+			fc = append(fc, tagOptionsDiff(f.Tag, f2.Tag, opts.TagOptionSeverity)...)
+		}
+
+		if len(fc) > 0 {
+			changes = append(changes, FieldChanged{j, f.Name, fc})
+		}
+	}
+
+	for i, f := range current {
+		if !ast.IsExported(f.Name) {
+			continue
+		}
+
+		if _, ok := seen[f.Name]; !ok {
+			fc := []Change{Added{}}
+			if opts.StrictFieldAdditions {
+				fc = append(fc, UnkeyedLiteralBroken{})
+			}
+			changes = append(changes, FieldChanged{i, f.Name, fc})
+		}
+	}
+
+	return changes
+}
+
+func hasUnexportedFields(fields []Field) bool {
+	for _, f := range fields {
+		if !ast.IsExported(f.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// functionalOptions returns the type of the options if the arguments of the
+// previous function were collapsed into variadic functional options in the
+// current one. Options must be of a named function or interface type, and
+// replace at least one argument.
+func functionalOptions(prev, current Func, opts DiffOptions) (types.Type, bool) {
+	last, ok := variadicElem(current)
+	if !ok || len(prev.Args) < len(current.Args) || !isNamed(last) {
+		return nil, false
+	}
+
+	switch last.Underlying().(type) {
+	case *types.Signature, *types.Interface:
+	default:
+		return nil, false
+	}
+
+	// The options must be new, and not just a variadic argument that was
+	// already there.
+	if prevLast, ok := variadicElem(prev); ok && typesEqual(prevLast, last, opts) {
+		return nil, false
+	}
+
+	return last, true
+}
+
+// variadicElem returns the type of the elements of the variadic argument of
+// the function, if any.
+func variadicElem(f Func) (types.Type, bool) {
+	if !f.Variadic || len(f.Args) == 0 {
+		return nil, false
+	}
+
+	s, ok := f.Args[len(f.Args)-1].Type.(*types.Slice)
+	if !ok {
+		return nil, false
+	}
+	return s.Elem(), true
+}
+
+// promotedFieldsDiff returns the changes of the fields promoted to a struct
+// that are no longer accessible as fields of it, e.g. because an embedded
+// field was removed. Changes in their types are reported as changes of the
+// structs they are declared in.
+func promotedFieldsDiff(prev, current Struct) []Change {
+	var fields = make(map[string]struct{})
+	for _, f := range current.Fields {
+		fields[f.Name] = struct{}{}
+	}
+	for _, f := range current.PromotedFields {
+		fields[f.Name] = struct{}{}
+	}
+
+	var changes []Change
+	for _, f := range prev.PromotedFields {
+		if _, ok := fields[f.Name]; !ok {
+			changes = append(changes, PromotedFieldRemoved{f.Name, f.Embedded})
+		}
+	}
+	return changes
+}
+
+// unexportedField returns the name of the unexported field among the given
+// fields that has the same name as the given exported one except for the
+// case, if any.
+func unexportedField(name string, fields []Field) (string, bool) {
+	for _, f := range fields {
+		if !ast.IsExported(f.Name) && strings.EqualFold(f.Name, name) {
+			return f.Name, true
+		}
+	}
+	return "", false
+}
+
+func interfacesDiff(prev, current []Interface, opts DiffOptions) []Change {
 	var changes []Change
 	currentInterfaces := interfacesIndex(current)
-	prevInterfaces := interfacesIndex(prev)
 
 	var seen = make(map[string]struct{})
-	for name, v := range prevInterfaces {
+	for _, v := range prev {
+		name := v.Name
 		seen[name] = struct{}{}
 		v2, ok := currentInterfaces[name]
 		if !ok {
-			changes = append(changes, NewDeclChange(name, InterfaceType, Removed{}))
+			if ast.IsExported(name) {
+				changes = append(changes, NewDeclChange(name, InterfaceType, Removed{}))
+			}
+			continue
 		}
 
-		_ = v
-		_ = v2
+		if dc := docDiff(v.Doc, v2.Doc, opts); len(dc) > 0 {
+			changes = append(changes, NewDeclChange(name, InterfaceType, dc...))
+		}
+
+		if tc := typeParamsDiff(v.TypeParams, v2.TypeParams, opts); len(tc) > 0 {
+			changes = append(changes, NewDeclChange(name, InterfaceType, tc...))
+		}
+
+		if !typeSetsEqual(v.TypeSet, v2.TypeSet, opts) {
+			changes = append(changes, NewDeclChange(name, InterfaceType, TypeSetChanged{
+				From: v.TypeSet,
+				To:   v2.TypeSet,
+			}))
+		}
 
-		// TODO: check methods
+		if mc := methodsDiff(v.Methods, v2.Methods, opts, true); len(mc) > 0 {
+			changes = append(changes, NewDeclChange(name, InterfaceType, mc...))
+		}
+
+		if opts.StdInterfaces {
+			if lc := lostInterfaces(v.Methods, v2.Methods); len(lc) > 0 {
+				changes = append(changes, NewDeclChange(name, InterfaceType, lc...))
+			}
+		}
 	}
 
-	for name := range currentInterfaces {
-		if _, ok := seen[name]; !ok {
+	for _, v := range current {
+		name := v.Name
+		if _, ok := seen[name]; !ok && ast.IsExported(name) {
 			changes = append(changes, NewDeclChange(name, InterfaceType, Added{}))
 		}
 	}
@@ -205,29 +878,367 @@ func interfacesDiff(prev, current []Interface) []Change {
 	return changes
 }
 
-func typesDiff(prev, current []TypeDef) []Change {
+// methodsDiff returns the changes in the methods of a type. Methods added to
+// interfaces break their implementations. Methods are matched by name, since
+// the order in which they are declared is not part of the API.
+func methodsDiff(prev, current []Func, opts DiffOptions, iface bool) []Change {
+	var changes []Change
+	currentMethods := funcsIndex(current)
+
+	var seen = make(map[string]struct{})
+	for _, m := range prev {
+		name := m.Name
+		seen[name] = struct{}{}
+		m2, ok := currentMethods[name]
+		if !ok {
+			changes = append(changes, MethodChanged{name, []Change{Removed{}}})
+			continue
+		}
+
+		mc := funcDiff(m, m2, opts)
+		// The type parameters of methods are the ones of their receiver.
+		if _, ok := typeParamsReordered(m.TypeParams, m2.TypeParams); ok {
+			mc = withoutReorderedTypes(mc, opts)
+		}
+		// Implementations of an interface must match the exact signature of
+		// its methods, so any change other than a cosmetic one breaks them,
+		// except for aliases reported in strict mode, which are identical.
+		nonStrict := opts
+		nonStrict.StrictAliases = false
+		if iface && maxSeverity(funcDiff(m, m2, nonStrict)) > Cosmetic {
+			mc = append(resultDirections(m, m2, mc), ImplementationsBroken{})
+		}
+
+		mc = append(mc, docDiff(m.Doc, m2.Doc, opts)...)
+		mc = append(mc, panicsDiff(m.Panics, m2.Panics)...)
+		if m.Embedded != "" && m2.Embedded == "" {
+			mc = append(mc, MethodShadowed{m.Embedded})
+		}
+
+		if m.PointerReceiver != m2.PointerReceiver {
+			mc = append(mc, ReceiverChanged{Pointer: m2.PointerReceiver})
+		}
+
+		if len(mc) > 0 {
+			changes = append(changes, MethodChanged{name, mc})
+		}
+	}
+
+	for _, m := range current {
+		name := m.Name
+		if _, ok := seen[name]; !ok {
+			mc := []Change{Added{}}
+			if iface {
+				mc = append(mc, ImplementationsBroken{})
+			}
+			changes = append(changes, MethodChanged{name, mc})
+		}
+	}
+
+	return changes
+}
+
+// funcDiff returns the changes in the arguments and results of a function.
+// Parameters are compared by position, since their names are not part of the
+// signature. If a context.Context was prepended to the arguments, the rest of
+// them are compared with the ones they were shifted from.
+func funcDiff(prev, current Func, opts DiffOptions) []Change {
+	var changes []Change
+	var args = current.Args
+	if contextPrepended(prev.Args, current.Args) {
+		changes = append(changes, ContextArgAdded{})
+		args = args[1:]
+	}
+
+	// offset is the difference between the positions of the arguments in
+	// args and their positions in the current function.
+	offset := len(current.Args) - len(args)
+	for i := 0; i < len(prev.Args) || i < len(args); i++ {
+		if i >= len(args) {
+			a := prev.Args[i]
+			changes = append(changes, ArgumentChanged{i, a.Name, a.Type, []Change{Removed{}}})
+			continue
+		}
+
+		a := args[i]
+		if i >= len(prev.Args) {
+			changes = append(changes, ArgumentChanged{i + offset, a.Name, a.Type, []Change{Added{}}})
+			continue
+		}
+
+		var ac []Change
+		if pointerChanged(prev.Args[i].Type, a.Type, opts) {
+			ac = append(ac, PointerChanged{From: prev.Args[i].Type, To: a.Type})
+		} else if argumentWidened(prev.Args[i].Type, a.Type) {
+			ac = append(ac, ArgumentWidened{From: prev.Args[i].Type, To: a.Type})
+		} else {
+			ac = paramDiff(prev.Args[i], a, opts)
+		}
+
+		// Only the last argument can be variadic.
+		last := i == len(prev.Args)-1 && i == len(args)-1
+		if last && prev.Variadic != current.Variadic {
+			ac = append(ac, VariadicChanged{Variadic: current.Variadic})
+		}
+
+		if opts.ReportCosmetic && prev.Args[i].Name != a.Name {
+			ac = append(ac, ParamRenamed{From: prev.Args[i].Name, To: a.Name})
+		}
+
+		if len(ac) > 0 {
+			changes = append(changes, ArgumentChanged{i + offset, a.Name, a.Type, ac})
+		}
+	}
+
+	for i := 0; i < len(prev.Return) || i < len(current.Return); i++ {
+		if i >= len(current.Return) {
+			r := prev.Return[i]
+			if isErrorType(r.Type) {
+				changes = append(changes, ErrorReturnRemoved{i})
+			} else {
+				changes = append(changes, ResultChanged{i, r.Type, []Change{Removed{}}})
+			}
+			continue
+		}
+
+		r := current.Return[i]
+		if i >= len(prev.Return) {
+			if isErrorType(r.Type) {
+				changes = append(changes, ErrorReturnAdded{i})
+			} else if isCleanupFunc(r.Type) {
+				changes = append(changes, CleanupReturnAdded{i})
+			} else {
+				changes = append(changes, ResultChanged{i, r.Type, []Change{Added{}}})
+			}
+			continue
+		}
+
+		var rc []Change
+		if isErrorType(r.Type) && isConcreteType(prev.Return[i].Type) {
+			rc = append(rc, ErrorTypeHidden{From: prev.Return[i].Type})
+		} else if pointerChanged(prev.Return[i].Type, r.Type, opts) {
+			rc = append(rc, PointerChanged{From: prev.Return[i].Type, To: r.Type, Result: true})
+			if _, ok := r.Type.(*types.Pointer); ok {
+				rc = append(rc, ResultMayBeNil{})
+			}
+		} else {
+			rc = paramDiff(prev.Return[i], r, opts)
+		}
+
+		if opts.ReportCosmetic && prev.Return[i].Name != r.Name {
+			rc = append(rc, ResultRenamed{From: prev.Return[i].Name, To: r.Name})
+		}
+
+		if len(rc) > 0 {
+			changes = append(changes, ResultChanged{i, r.Type, rc})
+		}
+	}
+
+	return changes
+}
+
+// typeSetsEqual reports whether the type set elements of two interfaces are
+// the same, regardless of their order.
+func typeSetsEqual(prev, current []types.Type, opts DiffOptions) bool {
+	if len(prev) != len(current) {
+		return false
+	}
+
+	var keys = make(map[string]int)
+	for _, t := range prev {
+		keys[typeSetElemKey(t, opts.qualifier())]++
+	}
+
+	for _, t := range current {
+		k := typeSetElemKey(t, nil)
+		if keys[k] == 0 {
+			return false
+		}
+		keys[k]--
+	}
+
+	return true
+}
+
+// typeSetElemKey returns the key of a type set element. The terms of unions
+// are sorted, since their order does not matter.
+func typeSetElemKey(t types.Type, qualifier types.Qualifier) string {
+	u, ok := t.(*types.Union)
+	if !ok {
+		return typeKey(t, qualifier)
+	}
+
+	var terms = make([]string, u.Len())
+	for i := 0; i < u.Len(); i++ {
+		terms[i] = typeKey(u.Term(i).Type(), qualifier)
+		if u.Term(i).Tilde() {
+			terms[i] = "~" + terms[i]
+		}
+	}
+	sort.Strings(terms)
+	return strings.Join(terms, " | ")
+}
+
+// docDiff returns the cosmetic change of a doc comment, if it changed and
+// cosmetic changes are reported.
+func docDiff(prev, current string, opts DiffOptions) []Change {
+	if !opts.ReportCosmetic || prev == current {
+		return nil
+	}
+	return []Change{DocChanged{From: prev, To: current}}
+}
+
+// contextPrepended reports whether a context.Context was added as the first
+// argument of a function.
+func contextPrepended(prev, current []Param) bool {
+	if len(current) <= len(prev) || !isContextType(current[0].Type) {
+		return false
+	}
+	return len(prev) == 0 || !isContextType(prev[0].Type)
+}
+
+func isContextType(t types.Type) bool {
+	name, ok := namedPath(t)
+	return ok && name == "context.Context"
+}
+
+// isErrorType reports whether the given type is the built-in error type.
+func isErrorType(t types.Type) bool {
+	if s, ok := t.(*serializedType); ok {
+		return s.key == "error"
+	}
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// isSentinelError reports whether the variable holds an error, either of
+// type error or, if it's named like a sentinel error, e.g. ErrNotFound, of
+// a type implementing it.
+func isSentinelError(v Var) bool {
+	if isErrorType(v.Type) {
+		return true
+	}
+
+	if !strings.HasPrefix(v.Name, "Err") || isSerialized(v.Type) {
+		return false
+	}
+
+	errorType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	return types.Implements(v.Type, errorType)
+}
+
+func isChan(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Chan)
+	return ok
+}
+
+// isCleanupFunc reports whether the type is a function without arguments
+// nor results, like the ones returned to clean up resources.
+func isCleanupFunc(t types.Type) bool {
+	sig, ok := t.Underlying().(*types.Signature)
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 0
+}
+
+// isConcreteType reports whether the type is not an interface.
+func isConcreteType(t types.Type) bool {
+	if _, ok := t.(*serializedType); ok {
+		return false
+	}
+
+	_, ok := t.Underlying().(*types.Interface)
+	return !ok
+}
+
+// pointerChanged reports whether one of the given types is a pointer to the
+// other, which must be a named type.
+func pointerChanged(prev, current types.Type, opts DiffOptions) bool {
+	if p, ok := current.(*types.Pointer); ok {
+		return isNamed(prev) && typesEqual(prev, p.Elem(), opts)
+	}
+
+	if p, ok := prev.(*types.Pointer); ok {
+		return isNamed(current) && typesEqual(p.Elem(), current, opts)
+	}
+
+	return false
+}
+
+func isNamed(t types.Type) bool {
+	_, ok := namedPath(t)
+	return ok
+}
+
+func paramDiff(prev, current Param, opts DiffOptions) []Change {
+	var changes []Change
+	if !typesEqual(prev.Type, current.Type, opts) {
+		if tc := typeArgsDiff(prev.Type, current.Type, opts); len(tc) > 0 {
+			return tc
+		}
+		changes = append(changes, TypeChanged{From: prev.Type, To: current.Type})
+	} else {
+		changes = append(changes, aliasSubstitution(prev.Type, current.Type, opts)...)
+	}
+	return changes
+}
+
+// typesDiff returns the changes between the type definitions of two
+// packages. The interfaces of both packages are used to find the ones no
+// longer implemented by values of the types.
+func typesDiff(prevPkg, currentPkg Package, opts DiffOptions) []Change {
 	var changes []Change
+	prev, current := prevPkg.Types, currentPkg.Types
 	currentTypes := typesIndex(current)
-	prevTypes := typesIndex(prev)
 
 	var seen = make(map[string]struct{})
-	for name, v := range prevTypes {
+	for _, v := range prev {
+		name := v.Name
 		seen[name] = struct{}{}
 		v2, ok := currentTypes[name]
 		if !ok {
-			changes = append(changes, NewDeclChange(name, TypeDefType, Removed{}))
+			if ast.IsExported(name) {
+				changes = append(changes, NewDeclChange(name, TypeDefType, Removed{}))
+			}
+			continue
 		}
 
-		if !typesEqual(v.Type, v2.Type) {
-			changes = append(changes, NewDeclChange(name, TypeDefType, TypeChanged{
-				From: v.Type,
-				To:   v2.Type,
-			}))
+		if dc := docDiff(v.Doc, v2.Doc, opts); len(dc) > 0 {
+			changes = append(changes, NewDeclChange(name, TypeDefType, dc...))
+		}
+
+		tc := typeParamsDiff(v.TypeParams, v2.TypeParams, opts)
+		switch {
+		case v.Alias != v2.Alias:
+			tc = append(tc, AliasChanged{Alias: v2.Alias, Type: v2.Type})
+		case !typesEqual(v.Type, v2.Type, opts) && v.Alias:
+			tc = append(tc, AliasRetargeted{From: v.Type, To: v2.Type})
+		case !typesEqual(v.Type, v2.Type, opts):
+			tc = append(tc, TypeChanged{From: v.Type, To: v2.Type})
+		default:
+			tc = append(tc, aliasSubstitution(v.Type, v2.Type, opts)...)
+		}
+
+		if len(tc) > 0 {
+			tc = append(tc, unexportedTypeChanges(v.Type, v2.Type)...)
+			changes = append(changes, NewDeclChange(name, TypeDefType, tc...))
+		}
+
+		if mc := methodsDiff(v.Methods, v2.Methods, opts, false); len(mc) > 0 {
+			changes = append(changes, NewDeclChange(name, TypeDefType, mc...))
+		}
+
+		if vc := valueInterfacesLost(v.Methods, v2.Methods, prevPkg, currentPkg, opts); len(vc) > 0 {
+			changes = append(changes, NewDeclChange(name, TypeDefType, vc...))
+		}
+
+		if opts.StdInterfaces {
+			if lc := lostInterfaces(v.Methods, v2.Methods); len(lc) > 0 {
+				changes = append(changes, NewDeclChange(name, TypeDefType, lc...))
+			}
 		}
 	}
 
-	for name := range currentTypes {
-		if _, ok := seen[name]; !ok {
+	for _, v := range current {
+		name := v.Name
+		if _, ok := seen[name]; !ok && ast.IsExported(name) {
 			changes = append(changes, NewDeclChange(name, TypeDefType, Added{}))
 		}
 	}
@@ -243,6 +1254,16 @@ func packagesIndex(a API) map[string]Package {
 	return result
 }
 
+// prevPackagesIndex indexes the packages of the previous API by the path
+// they have in the current API.
+func prevPackagesIndex(a API, opts DiffOptions) map[string]Package {
+	var result = make(map[string]Package)
+	for _, p := range a {
+		result[opts.modulePath(p.Path)] = p
+	}
+	return result
+}
+
 func constsIndex(xs []Const) map[string]Const {
 	var result = make(map[string]Const)
 	for _, x := range xs {
@@ -283,6 +1304,15 @@ func structsIndex(xs []Struct) map[string]Struct {
 	return result
 }
 
+// fieldsIndex returns the position of each field by name.
+func fieldsIndex(xs []Field) map[string]int {
+	var result = make(map[string]int)
+	for i, x := range xs {
+		result[x.Name] = i
+	}
+	return result
+}
+
 func typesIndex(xs []TypeDef) map[string]TypeDef {
 	var result = make(map[string]TypeDef)
 	for _, x := range xs {
@@ -291,6 +1321,14 @@ func typesIndex(xs []TypeDef) map[string]TypeDef {
 	return result
 }
 
-func typesEqual(a, b types.Type) bool {
-	return false
+// typesEqual reports whether two types, which may come from different
+// loads of the project, are the same. Types are compared by their key
+// because types coming from different loads are never identical for the
+// type checker.
+func typesEqual(prev, current types.Type, opts DiffOptions) bool {
+	if prev == nil || current == nil {
+		return prev == current
+	}
+
+	return typeKey(prev, opts.qualifier()) == typeKey(current, nil)
 }
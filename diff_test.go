@@ -0,0 +1,1367 @@
+package semverlint
+
+import (
+	"bytes"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestStrictFieldAdditions(t *testing.T) {
+	prev := `type Point struct{ X, Y int }`
+	current := `type Point struct{ X, Y, Z int }`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: struct Point: field "Z" at position 2: was added`,
+	})
+
+	changes := diffSourcesWithOptions(t, prev, current, DiffOptions{StrictFieldAdditions: true})
+	AssertChanges(t, changes, []string{
+		`example.com/m: struct Point: field "Z" at position 2: was added, breaks composite literals without field names`,
+	})
+
+	if Recommend(changes) != MajorBump {
+		t.Error("expected field additions to be breaking with StrictFieldAdditions")
+	}
+}
+
+func TestFieldsReordered(t *testing.T) {
+	prev := `
+import "sync"
+
+type Point struct{ X, Y int }
+
+type Config struct {
+	Name string
+	Port int
+	mu   sync.Mutex
+}
+`
+	current := `
+import "sync"
+
+type Point struct{ Y, X int }
+
+type Config struct {
+	Port int
+	Name string
+	mu   sync.Mutex
+}
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: struct Point: field "X" at position 1: position changed from 0 to 1, field "Y" at position 0: position changed from 1 to 0`,
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+
+	// Only Point can be built with unkeyed literals.
+	changes = diffSourcesWithOptions(t, prev, current, DiffOptions{ReportCosmetic: true})
+	AssertChanges(t, changes, []string{
+		`example.com/m: struct Config: field "Name" at position 1: position changed from 0 to 1, but the struct can only be built with keyed literals, field "Port" at position 0: position changed from 1 to 0, but the struct can only be built with keyed literals`,
+		`example.com/m: struct Point: field "X" at position 1: position changed from 0 to 1, field "Y" at position 0: position changed from 1 to 0`,
+	})
+
+	for _, pkg := range changes {
+		for _, c := range pkg.Changes {
+			d := c.(DeclChange)
+			want := Breaking
+			if d.Name == "Config" {
+				want = Cosmetic
+			}
+
+			if s := SeverityOf(d); s != want {
+				t.Errorf("expected changes of %s to be %s, got %s", d.Name, want, s)
+			}
+		}
+	}
+}
+
+func TestDiffFiltered(t *testing.T) {
+	prev := moduleAPI(t, map[string]string{
+		"a/a.go": "package a\n\nfunc A() {}\n",
+		"b/b.go": "package b\n\nfunc B() {}\n",
+	})
+	current := moduleAPI(t, map[string]string{
+		"a/a.go": "package a\n\nfunc A() {}\n\nfunc A2() {}\n",
+		"b/b.go": "package b\n\nfunc B() {}\n\nfunc B2() {}\n",
+	})
+
+	AssertChanges(t, DiffFiltered(current, prev, []string{"example.com/m/b"}), []string{
+		"example.com/m/b: function B2: was added",
+	})
+}
+
+func TestParamRenamed(t *testing.T) {
+	prev := `
+func F(a int) {}
+
+type I interface{ M(a int) }
+`
+	current := `
+func F(b int) {}
+
+type I interface{ M(b int) }
+`
+
+	AssertChanges(t, diffSources(t, prev, current), nil)
+
+	changes := diffSourcesWithOptions(t, prev, current, DiffOptions{ReportCosmetic: true})
+	AssertChanges(t, changes, []string{
+		`example.com/m: function F: argument b with type int at position 0: renamed from "a" to "b"`,
+		`example.com/m: interface I: method M: argument b with type int at position 0: renamed from "a" to "b"`,
+	})
+
+	for _, pkg := range changes {
+		for _, c := range pkg.Changes {
+			if s := SeverityOf(c); s != Cosmetic {
+				t.Errorf("expected %s to be cosmetic, got %s", c, s)
+			}
+		}
+	}
+
+	if b := Recommend(changes); b != PatchBump {
+		t.Errorf("expected %s bump, got %s", PatchBump, b)
+	}
+}
+
+func TestParamRenamedInFuncTypes(t *testing.T) {
+	prev := `
+type H func(a int) (n int)
+
+var V func(a int)
+
+func F(cb func(a int)) {}
+
+type I interface{ M(cb func(a int)) }
+`
+	current := `
+type H func(b int) (m int)
+
+var V func(b int)
+
+func F(cb func(b int)) {}
+
+type I interface{ M(cb func(b int)) }
+`
+
+	changes := diffSourcesWithOptions(t, prev, current, DiffOptions{ReportCosmetic: true})
+	AssertChanges(t, changes, nil)
+
+	if b := Recommend(changes); b != PatchBump {
+		t.Errorf("expected %s bump, got %s", PatchBump, b)
+	}
+
+	AssertChanges(t, diffSources(t, prev, `
+type H func(a string) (n int)
+
+var V func(a int) error
+
+func F(cb func(a, b int)) {}
+
+type I interface{ M(cb func(a int)) }
+`), []string{
+		`example.com/m: function F: argument cb with type func(a int, b int) at position 0: type changed from "func(a int)" to "func(a int, b int)"`,
+		`example.com/m: package-level variable V: type changed from "func(a int)" to "func(a int) error"`,
+		`example.com/m: type definition H: type changed from "func(a int) (n int)" to "func(a string) (n int)"`,
+	})
+}
+
+func TestErrorReturn(t *testing.T) {
+	prev := `func F() int { panic("") }`
+	current := `func F() (int, error) { panic("") }`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		"example.com/m: function F: error result added at position 1, callers must now handle it",
+	})
+
+	AssertChanges(t, diffSources(t, current, prev), []string{
+		"example.com/m: function F: error result at position 1 was removed",
+	})
+}
+
+func TestKindChanged(t *testing.T) {
+	prev := `var Timeout = 5`
+	current := `func Timeout() int { return 5 }`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		"example.com/m: package-level variable Timeout: changed from package-level variable to function",
+	})
+}
+
+func TestIsCompatible(t *testing.T) {
+	prev := sourceAPI(t, `
+func F() {}
+
+func G() {}
+`)
+
+	if !IsCompatible(prev, prev) {
+		t.Error("expected the same API to be compatible")
+	}
+
+	if IsCompatible(prev, sourceAPI(t, `func F() {}`)) {
+		t.Error("expected an API with a removed function to be incompatible")
+	}
+}
+
+func TestTypeSetChanged(t *testing.T) {
+	prev := `type Key interface{ ~int | ~string }`
+	current := `type Key interface{ ~int }`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: interface Key: type set changed from "~int | ~string" to "~int"`,
+	})
+
+	if !IsBreaking(changes[0].Changes[0]) {
+		t.Error("expected type set changes to be breaking")
+	}
+}
+
+func TestModulePaths(t *testing.T) {
+	prev := moduleAPI(t, map[string]string{
+		"m.go":     "package m\n\ntype T struct{}\n\nfunc F() T { return T{} }\n",
+		"sub/s.go": "package sub\n\nfunc G() {}\n",
+	})
+	current := moduleAPI(t, map[string]string{
+		"go.mod":   "module example.com/m/v2\n\ngo 1.26\n",
+		"m.go":     "package m\n\ntype T struct{}\n\nfunc F() T { return T{} }\n",
+		"sub/s.go": "package sub\n\nfunc G(n int) {}\n",
+	})
+
+	opts := DiffOptions{ModulePaths: map[string]string{"example.com/m": "example.com/m/v2"}}
+	AssertChanges(t, DiffWithOptions(current, prev, opts), []string{
+		"example.com/m/v2/sub: function G: argument n with type int at position 0: was added",
+	})
+}
+
+func TestModulePathsOverlapping(t *testing.T) {
+	opts := DiffOptions{ModulePaths: map[string]string{
+		"example.com/m":     "example.com/m/v2",
+		"example.com/m/sub": "example.com/sub/v3",
+	}}
+
+	cases := map[string]string{
+		"example.com/m":         "example.com/m/v2",
+		"example.com/m/a":       "example.com/m/v2/a",
+		"example.com/m/sub":     "example.com/sub/v3",
+		"example.com/m/sub/b":   "example.com/sub/v3/b",
+		"example.com/m/subpkg":  "example.com/m/v2/subpkg",
+		"example.com/other/pkg": "example.com/other/pkg",
+	}
+
+	// The mappings are a map, so try several times to catch results that
+	// depend on the order they are iterated in.
+	for i := 0; i < 20; i++ {
+		for path, want := range cases {
+			if got := opts.modulePath(path); got != want {
+				t.Fatalf("%s: expected %s, got %s", path, want, got)
+			}
+		}
+	}
+}
+
+func TestInterfaceMethodArgumentChanged(t *testing.T) {
+	prev := `
+import "os"
+
+type Loader interface{ Load(f *os.File) error }
+`
+	current := `
+import "io"
+
+type Loader interface{ Load(f io.Reader) error }
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: interface Loader: method Load: argument f with type io.Reader at position 0: type widened from "*os.File" to "io.Reader", which it implements, callers passing the previous type still work, existing implementations no longer satisfy the interface`,
+	})
+
+	if b := Recommend(changes); b != MajorBump {
+		t.Errorf("expected %s bump, got %s", MajorBump, b)
+	}
+}
+
+func TestConstructorNote(t *testing.T) {
+	prev := `
+type Client struct{ Addr string }
+
+func NewClient(addr string) *Client { return &Client{Addr: addr} }
+`
+	current := `
+type Client struct {
+	Addr    string
+	Retries int
+}
+
+func NewClient(addr string) *Client { return &Client{Addr: addr, Retries: 3} }
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: struct Client: field "Retries" at position 1: was added, struct has constructor NewClient, check the zero value of the field is valid for composite literals`,
+	})
+
+	// Without a constructor, the struct is meant to be created with
+	// composite literals, so there's nothing to note.
+	prev = `type Client struct{ Addr string }`
+	current = `
+type Client struct {
+	Addr    string
+	Retries int
+}
+`
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: struct Client: field "Retries" at position 1: was added`,
+	})
+}
+
+func TestUnexportedTypeReferenced(t *testing.T) {
+	prev := `
+type Item struct{}
+
+type List []Item
+`
+	current := `
+type item struct{}
+
+type List []item
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		"example.com/m: struct Item: was removed",
+		`example.com/m: type definition List: type changed from "[]example.com/m.Item" to "[]example.com/m.item", now refers to unexported type example.com/m.item, which users can't name`,
+	})
+}
+
+func TestPointerChanged(t *testing.T) {
+	prev := `
+type Config struct{}
+
+func F(cfg Config) {}
+`
+	current := `
+type Config struct{}
+
+func F(cfg *Config) {}
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: function F: argument cfg with type *example.com/m.Config at position 0: parameter changed from value to pointer ("example.com/m.Config" to "*example.com/m.Config")`,
+	})
+
+	AssertChanges(t, diffSources(t, current, prev), []string{
+		`example.com/m: function F: argument cfg with type example.com/m.Config at position 0: parameter changed from pointer to value ("*example.com/m.Config" to "example.com/m.Config")`,
+	})
+}
+
+func TestResultMayBeNil(t *testing.T) {
+	prev := `
+type Config struct{}
+
+func Load() (Config, error) { return Config{}, nil }
+`
+	current := `
+type Config struct{}
+
+func Load() (*Config, error) { return nil, nil }
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: function Load: result with type *example.com/m.Config at position 0: result changed from value to pointer ("example.com/m.Config" to "*example.com/m.Config"), result may now be nil, callers must check it before using it`,
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+
+	AssertChanges(t, diffSources(t, current, prev), []string{
+		`example.com/m: function Load: result with type example.com/m.Config at position 0: result changed from pointer to value ("*example.com/m.Config" to "example.com/m.Config")`,
+	})
+
+	// The kind of parameter is kept when the changes are read back.
+	var b bytes.Buffer
+	if err := WriteChanges(&b, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadChanges(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AssertChanges(t, read, changes.Strings())
+}
+
+func TestMethodShadowed(t *testing.T) {
+	prev := `
+type Base struct{}
+
+func (Base) Close() error { return nil }
+
+type Conn struct {
+	Base
+}
+`
+	current := `
+type Base struct{}
+
+func (Base) Close() error { return nil }
+
+type Conn struct {
+	Base
+}
+
+func (Conn) Close() error { return nil }
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		"example.com/m: struct Conn: method Close: now shadows the method promoted from the embedded field Base",
+	})
+
+	if r := Recommend(changes); r != PatchBump {
+		t.Errorf("expected a patch bump for a shadowed method, got %s", r)
+	}
+}
+
+func TestIotaShifted(t *testing.T) {
+	prev := `
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+const Max = 10
+`
+	current := `
+type Color int
+
+const (
+	Red Color = iota
+	Yellow
+	Green
+	Blue
+)
+
+const Max = 11
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		"example.com/m: package-level constant Blue: value changed from 2 to 3, shifted by 1 along with other constants, probably by a change in an iota block",
+		"example.com/m: package-level constant Green: value changed from 1 to 2, shifted by 1 along with other constants, probably by a change in an iota block",
+		"example.com/m: package-level constant Max: value changed from 10 to 11",
+		"example.com/m: package-level constant Yellow: was added",
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump for shifted constants, got %s", bump)
+	}
+
+	if IsCompatible(sourceAPI(t, prev), sourceAPI(t, current)) {
+		t.Error("expected shifted constants to be incompatible")
+	}
+
+	changes = diffSources(t, "const A = 1", "const A = 2")
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump for a changed constant, got %s", bump)
+	}
+}
+
+func TestFieldUnexported(t *testing.T) {
+	prev := `
+type User struct {
+	Name string
+	Age  int
+}
+`
+	current := `
+type User struct {
+	name string
+	Age  int
+}
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: struct User: field "Name" at position 0: was removed, was unexported as name`,
+	})
+
+	if r := Recommend(changes); r != MajorBump {
+		t.Errorf("expected a major bump, got %s", r)
+	}
+}
+
+func TestInterfaceMethodsReordered(t *testing.T) {
+	prev := `
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	Delete(key string) error
+}
+`
+	reordered := `
+type Store interface {
+	Delete(key string) error
+	Put(key string, value []byte) error
+	Get(key string) ([]byte, error)
+}
+`
+	changed := `
+type Store interface {
+	Delete(key string) error
+	Put(key string, value []byte) error
+	Get(key []byte) ([]byte, error)
+}
+`
+
+	AssertChanges(t, diffSources(t, prev, reordered), nil)
+	AssertChanges(t, diffSources(t, prev, changed), []string{
+		`example.com/m: interface Store: method Get: argument key with type []byte at position 0: type changed from "string" to "[]byte", existing implementations no longer satisfy the interface`,
+	})
+}
+
+func TestPackageNameChanged(t *testing.T) {
+	prev := moduleAPI(t, map[string]string{
+		"foo/foo.go": "package foo\n\nfunc F() {}\n",
+	})
+	current := moduleAPI(t, map[string]string{
+		"foo/foo.go": "package foobar\n\nfunc F() {}\n",
+	})
+
+	changes := Diff(current, prev)
+	AssertChanges(t, changes, []string{
+		"example.com/m/foo: package foobar: name changed from foo to foobar",
+	})
+
+	if r := Recommend(changes); r != MajorBump {
+		t.Errorf("expected a major bump, got %s", r)
+	}
+}
+
+func TestFunctionalOptionsRefactor(t *testing.T) {
+	prev := `
+type Server struct{}
+
+type Option func(*Server)
+
+func NewServer(addr string, timeout int) *Server { return nil }
+`
+	current := `
+type Server struct{}
+
+type Option func(*Server)
+
+func NewServer(opts ...Option) *Server { return nil }
+`
+
+	opts := DiffOptions{DetectFunctionalOptions: true}
+	changes := diffSourcesWithOptions(t, prev, current, opts)
+	AssertChanges(t, changes, []string{
+		`example.com/m: function NewServer: argument opts with type []example.com/m.Option at position 0: type changed from "string" to "[]example.com/m.Option", argument timeout with type int at position 1: was removed, arguments were probably replaced by functional options of type example.com/m.Option`,
+	})
+
+	if r := Recommend(changes); r != MajorBump {
+		t.Errorf("expected the refactor to still require a major bump, got %s", r)
+	}
+
+	for _, c := range diffSources(t, prev, current).Strings() {
+		if strings.Contains(c, "functional options") {
+			t.Errorf("expected no annotation without the option, got %s", c)
+		}
+	}
+}
+
+func TestUnexportedTypeMethods(t *testing.T) {
+	prev := `
+type client struct {
+	Addr string
+	conn int
+}
+
+func (c *client) Send(msg string) error { return nil }
+func (c *client) close() {}
+
+func New() *client { return nil }
+`
+	current := `
+type client struct {
+	Addr string
+	conn int
+}
+
+func (c *client) Send(msg []byte) error { return nil }
+
+func New() *client { return nil }
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: struct client: method Send: argument msg with type []byte at position 0: type changed from "string" to "[]byte"`,
+	})
+
+	// Unexported types that can't be reached through the API are not part
+	// of it.
+	AssertChanges(t, diffSources(t, `
+type helper struct{}
+
+func (helper) Do(n int) {}
+`, `
+type helper struct{}
+
+func (helper) Do(n string) {}
+`), nil)
+}
+
+func TestErrorTypeHidden(t *testing.T) {
+	prev := `
+type MyError struct{ Code int }
+
+func (e *MyError) Error() string { return "" }
+
+func F() *MyError { return nil }
+
+func G() (int, interface{ Error() string }) { return 0, nil }
+`
+	current := `
+type MyError struct{ Code int }
+
+func (e *MyError) Error() string { return "" }
+
+func F() error { return nil }
+
+func G() (int, error) { return 0, nil }
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: function F: result with type error at position 0: type changed from concrete type "*example.com/m.MyError" to the error interface, callers can no longer use it without a type assertion`,
+		`example.com/m: function G: result with type error at position 1: type changed from "interface{Error() string}" to "error"`,
+	})
+}
+
+func TestChannelVarChanged(t *testing.T) {
+	prev := `
+type Event struct{}
+
+var Events chan Event
+
+var Done chan struct{}
+
+var Errors chan error
+
+var Names []string
+`
+	current := `
+type Event struct{}
+
+var Events []Event
+
+var Done <-chan struct{}
+
+var Errors chan string
+
+var Names chan string
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: package-level variable Done: channel direction changed from bidirectional to receive-only`,
+		`example.com/m: package-level variable Errors: channel type changed from "chan error" to "chan string"`,
+		`example.com/m: package-level variable Events: changed from channel type "chan example.com/m.Event" to non-channel type "[]example.com/m.Event"`,
+		`example.com/m: package-level variable Names: changed from non-channel type "[]string" to channel type "chan string"`,
+	})
+}
+
+func TestCleanupReturnAdded(t *testing.T) {
+	prev := `
+type DB struct{}
+
+func Open() *DB { return nil }
+
+func Dial() *DB { return nil }
+`
+	current := `
+type DB struct{}
+
+func Open() (*DB, func()) { return nil, nil }
+
+func Dial() (*DB, func() error) { return nil, nil }
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		"example.com/m: function Dial: result with type func() error at position 1: was added",
+		"example.com/m: function Open: cleanup function result added at position 1, callers must now call it when done",
+	})
+}
+
+func TestPromotedFieldRemoved(t *testing.T) {
+	prev := `
+type Base struct {
+	ID      int
+	Created string
+	secret  string
+}
+
+type User struct {
+	Base
+	Name string
+}
+`
+	current := `
+type Base struct {
+	ID      int
+	Created string
+	secret  string
+}
+
+type User struct {
+	Name string
+	ID   int
+}
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: struct User: field "Base" at position 0: was removed, field "Name" at position 0: position changed from 1 to 0, field "ID" at position 1: was added, field "Created" promoted from embedded field Base was removed`,
+	})
+}
+
+func TestContextArgAdded(t *testing.T) {
+	prev := `
+func Get(key string) error { return nil }
+
+func List() error { return nil }
+
+func Put(key string, value int) error { return nil }
+`
+	current := `
+import "context"
+
+func Get(ctx context.Context, key string) error { return nil }
+
+func List(ctx context.Context) error { return nil }
+
+func Put(ctx context.Context, key string, value string) error { return nil }
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		"example.com/m: function Get: context.Context argument prepended, callers must now pass a context",
+		"example.com/m: function List: context.Context argument prepended, callers must now pass a context",
+		`example.com/m: function Put: context.Context argument prepended, callers must now pass a context, argument value with type string at position 2: type changed from "int" to "string"`,
+	})
+
+	if r := Recommend(changes); r != MajorBump {
+		t.Errorf("expected a major bump, got %s", r)
+	}
+}
+
+func TestAliasRetargeted(t *testing.T) {
+	testCases := []struct {
+		name          string
+		prev, current string
+		want          []string
+	}{
+		{
+			"retargeted",
+			`
+type X struct{}
+type Y struct{}
+type A = X
+`,
+			`
+type X struct{}
+type Y struct{}
+type A = Y
+`,
+			[]string{`example.com/m: type definition A: alias target changed from example.com/m.X to example.com/m.Y`},
+		},
+		{
+			"retargeted to a builtin",
+			`type A = int`,
+			`type A = int64`,
+			[]string{`example.com/m: type definition A: alias target changed from int to int64`},
+		},
+		{
+			"defined type underlying change",
+			`type A int`,
+			`type A int64`,
+			[]string{`example.com/m: type definition A: type changed from "int" to "int64"`},
+		},
+		{
+			"became an alias",
+			`
+type A int
+type B int
+`,
+			`
+type A = int
+type B = A
+`,
+			[]string{
+				`example.com/m: type definition A: became an alias of int`,
+				`example.com/m: type definition B: became an alias of example.com/m.A`,
+			},
+		},
+		{
+			"no longer an alias",
+			`type A = int`,
+			`type A int`,
+			[]string{`example.com/m: type definition A: is no longer an alias, now a defined type with underlying type int`},
+		},
+		{
+			"same target",
+			`type A = int`,
+			`type A = int`,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := diffSources(t, tc.prev, tc.current)
+			AssertChanges(t, changes, tc.want)
+
+			if major := Recommend(changes) == MajorBump; major != (len(tc.want) > 0) {
+				t.Errorf("expected major bump to be %v, got %s", len(tc.want) > 0, Recommend(changes))
+			}
+		})
+	}
+}
+
+func FuzzDiff(f *testing.F) {
+	intType := types.Typ[types.Int]
+	stringType := types.Typ[types.String]
+
+	seeds := []API{
+		nil,
+		{},
+		// Empty packages, with nil and empty slices.
+		{{Name: "a", Path: "example.com/m/a"}},
+		{{
+			Name:       "a",
+			Path:       "example.com/m/a",
+			Vars:       []Var{},
+			Consts:     []Const{},
+			Funcs:      []Func{},
+			Structs:    []Struct{},
+			Interfaces: []Interface{},
+			Types:      []TypeDef{},
+		}},
+		// Declarations with nil slices and without names.
+		{{
+			Name:       "a",
+			Path:       "example.com/m/a",
+			Funcs:      []Func{{Name: "F"}, {}},
+			Structs:    []Struct{{Name: "S"}, {}},
+			Interfaces: []Interface{{Name: "I"}},
+			Types:      []TypeDef{{Name: "T", Type: intType}},
+		}},
+		// Duplicate names of packages, declarations, fields and methods.
+		{
+			{Name: "a", Path: "example.com/m/a"},
+			{
+				Name: "a",
+				Path: "example.com/m/a",
+				Vars: []Var{{Name: "X", Type: intType}, {Name: "X", Type: stringType}},
+				Consts: []Const{
+					{Name: "X", Type: intType, Value: "1"},
+					{Name: "X", Type: intType, Value: "2"},
+				},
+				Funcs: []Func{
+					{Name: "X", Args: []Param{{Name: "a", Type: intType}, {Name: "a", Type: intType}}},
+					{Name: "X", Variadic: true},
+				},
+				Structs: []Struct{{
+					Name:           "S",
+					Fields:         []Field{{Name: "F", Type: intType}, {Name: "F", Type: stringType}},
+					PromotedFields: []PromotedField{{Name: "F"}, {Name: "F", Embedded: "F"}},
+					Methods:        []Func{{Name: "M"}, {Name: "M", PointerReceiver: true}},
+				}},
+				Interfaces: []Interface{
+					{Name: "S", Methods: []Func{{Name: "M"}, {Name: "M"}}},
+					{Name: "I", TypeSet: []types.Type{intType, intType}},
+				},
+				Types: []TypeDef{
+					{Name: "T", Type: intType, Alias: true},
+					{Name: "T", Type: types.NewPointer(intType)},
+				},
+			},
+		},
+		sourceAPI(f, `
+import "context"
+
+type S struct {
+	A int
+	B *S
+}
+
+func (*S) M(context.Context, ...string) (S, error) { return S{}, nil }
+
+type I interface{ M(context.Context, ...string) (S, error) }
+
+type C[T ~int | ~string] struct{ V T }
+
+type A = map[string][]chan<- *C[int]
+
+const X = iota
+
+var V func(I) A
+`),
+	}
+
+	var encoded [][]byte
+	for _, api := range seeds {
+		var buf bytes.Buffer
+		if err := WriteAPI(&buf, api); err != nil {
+			f.Fatal(err)
+		}
+
+		// Seeds that can't be read back would only test ReadAPI.
+		if _, err := ReadAPI(bytes.NewReader(buf.Bytes())); err != nil {
+			f.Fatal(err)
+		}
+		encoded = append(encoded, buf.Bytes())
+	}
+
+	for _, prev := range encoded {
+		for _, current := range encoded {
+			f.Add(prev, current)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, prevJSON, currentJSON []byte) {
+		prev, err := ReadAPI(bytes.NewReader(prevJSON))
+		if err != nil {
+			return
+		}
+
+		current, err := ReadAPI(bytes.NewReader(currentJSON))
+		if err != nil {
+			return
+		}
+
+		for _, opts := range []DiffOptions{
+			{},
+			{
+				StrictFieldAdditions:    true,
+				ReportCosmetic:          true,
+				RenameThreshold:         2,
+				StdInterfaces:           true,
+				StdShadowing:            true,
+				DetectFunctionalOptions: true,
+				StrictAliases:           true,
+				StrictAliasSeverity:     Breaking,
+			},
+		} {
+			changes := DiffWithOptions(current, prev, opts)
+			_ = changes.Strings()
+			_ = Recommend(changes)
+		}
+	})
+}
+
+func TestResultRenamed(t *testing.T) {
+	prev := `
+func F() (n int, err error) { return 0, nil }
+
+func G() (int, error) { return 0, nil }
+
+func H() (a, b int) { return 0, 0 }
+`
+	current := `
+func F() (int, error) { return 0, nil }
+
+func G() (n int, err error) { return 0, nil }
+
+func H() (x, b int) { return 0, 0 }
+`
+
+	AssertChanges(t, diffSources(t, prev, current), nil)
+
+	changes := diffSourcesWithOptions(t, prev, current, DiffOptions{ReportCosmetic: true})
+	AssertChanges(t, changes, []string{
+		`example.com/m: function F: result with type int at position 0: no longer named "n", result with type error at position 1: no longer named "err"`,
+		`example.com/m: function G: result with type int at position 0: now named "n", result with type error at position 1: now named "err"`,
+		`example.com/m: function H: result with type int at position 0: renamed from "a" to "x"`,
+	})
+
+	if bump := Recommend(changes); bump != PatchBump {
+		t.Errorf("expected a patch bump, got %s", bump)
+	}
+}
+
+func TestEnumValueRemoved(t *testing.T) {
+	prev := `
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+type Size int
+
+const Small Size = 0
+
+const Limit = 10
+`
+	current := `
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	_
+)
+
+type Size int
+
+const Max = 10
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: package-level constant Blue: was removed, was one of the values of the enum type Color`,
+		`example.com/m: package-level constant Limit: was removed`,
+		`example.com/m: package-level constant Max: was added`,
+		`example.com/m: package-level constant Small: was removed`,
+	})
+}
+
+func TestVariadicChanged(t *testing.T) {
+	variadic := `
+func F(xs ...int) {}
+
+func G(prefix string, xs ...string) {}
+`
+	slices := `
+func F(xs []int) {}
+
+func G(prefix string, xs []string) {}
+`
+
+	changes := diffSources(t, variadic, slices)
+	AssertChanges(t, changes, []string{
+		`example.com/m: function F: argument xs with type []int at position 0: is no longer variadic, callers must now pass a slice instead of separate values`,
+		`example.com/m: function G: argument xs with type []string at position 1: is no longer variadic, callers must now pass a slice instead of separate values`,
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+
+	AssertChanges(t, diffSources(t, slices, variadic), []string{
+		`example.com/m: function F: argument xs with type []int at position 0: became variadic, callers passing a slice must now expand it with ...`,
+		`example.com/m: function G: argument xs with type []string at position 1: became variadic, callers passing a slice must now expand it with ...`,
+	})
+
+	// Only the last argument can be variadic, so changing the arguments
+	// before it is not a variadic change.
+	AssertChanges(t, diffSources(t, `func F(xs ...int) {}`, `func F(xs []int, ys ...int) {}`), []string{
+		`example.com/m: function F: argument ys with type []int at position 1: was added`,
+	})
+}
+
+func TestOpacityChanged(t *testing.T) {
+	exposed := `
+type Config struct {
+	Addr    string
+	Timeout int
+	secret  string
+}
+`
+	opaque := `
+type Config struct {
+	addr    string
+	timeout int
+	secret  string
+}
+`
+
+	changes := diffSources(t, exposed, opaque)
+	AssertChanges(t, changes, []string{
+		`example.com/m: struct Config: field "Addr" at position 0: was removed, was unexported as addr, field "Timeout" at position 1: was removed, was unexported as timeout, became opaque, it no longer has exported fields`,
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+
+	changes = diffSources(t, opaque, exposed)
+	AssertChanges(t, changes, []string{
+		`example.com/m: struct Config: field "Addr" at position 0: was added, field "Timeout" at position 1: was added, is no longer opaque, it now has exported fields`,
+	})
+
+	if bump := Recommend(changes); bump != MinorBump {
+		t.Errorf("expected a minor bump, got %s", bump)
+	}
+
+	// Structs without fields were always opaque.
+	AssertChanges(t, diffSources(t, `type Empty struct{}`, `type Empty struct{ x int }`), nil)
+}
+
+func TestConstructorRemoved(t *testing.T) {
+	testCases := []struct {
+		name          string
+		prev, current string
+		want          []string
+	}{
+		{
+			"sole constructor removed",
+			`
+type Client struct{ addr string }
+
+func NewClient(addr string) *Client { return &Client{addr} }
+
+func (c *Client) Clone() *Client { return c }
+`,
+			`
+type Client struct{ addr string }
+
+func (c *Client) Clone() *Client { return c }
+`,
+			[]string{
+				`example.com/m: function NewClient: was removed`,
+				`example.com/m: struct Client: constructor NewClient was removed and the struct has no exported fields, so it can no longer be created`,
+			},
+		},
+		{
+			"other function returns it",
+			`
+type Client struct{ addr string }
+
+func NewClient(addr string) *Client { return &Client{addr} }
+`,
+			`
+type Client struct{ addr string }
+
+func Dial(addr string) (*Client, error) { return &Client{addr}, nil }
+`,
+			[]string{
+				`example.com/m: function Dial: was added`,
+				`example.com/m: function NewClient: was removed`,
+			},
+		},
+		{
+			"exported fields",
+			`
+type Client struct{ Addr string }
+
+func NewClient(addr string) *Client { return &Client{addr} }
+`,
+			`type Client struct{ Addr string }`,
+			[]string{`example.com/m: function NewClient: was removed`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			AssertChanges(t, diffSources(t, tc.prev, tc.current), tc.want)
+		})
+	}
+}
+
+func TestConstValuesEqual(t *testing.T) {
+	testCases := []struct {
+		prev, current string
+		equal         bool
+	}{
+		{"1.5", "3/2", true},
+		{"3/2", "6/4", true},
+		{"-1.5", "-3/2", true},
+		{"0.25", "1/4", true},
+		{"1e3", "1000", true},
+		{"0x10", "16", true},
+		{"1.5", "-3/2", false},
+		{"1/3", "0.333333", false},
+		{"1", "2", false},
+		{`"a"`, `"a"`, true},
+		{`"a"`, `"b"`, false},
+		{`"1.5"`, "1.5", false},
+		{"true", "true", true},
+		{"true", "false", false},
+		{"1/0", "1/0", true},
+		{"1/0", "2/0", false},
+	}
+
+	for _, tc := range testCases {
+		if got := constValuesEqual(tc.prev, tc.current); got != tc.equal {
+			t.Errorf("%s == %s: expected %v, got %v", tc.prev, tc.current, tc.equal, got)
+		}
+	}
+}
+
+func TestConstValueRepresentation(t *testing.T) {
+	api := func(value string) API {
+		return API{{
+			Name: "m",
+			Path: "example.com/m",
+			Consts: []Const{{
+				Name:  "Ratio",
+				Type:  types.Typ[types.UntypedFloat],
+				Value: value,
+			}},
+		}}
+	}
+
+	AssertChanges(t, Diff(api("3/2"), api("1.5")), nil)
+	AssertChanges(t, Diff(api("-0.5"), api("-1/2")), nil)
+	AssertChanges(t, Diff(api("5/3"), api("1.5")), []string{
+		`example.com/m: package-level constant Ratio: value changed from 1.5 to 5/3`,
+	})
+}
+
+func TestDeclarationsMovedBetweenFiles(t *testing.T) {
+	opts := LoadOptions{Docs: true, Files: true, Panics: true}
+	load := func(files map[string]string) API {
+		t.Helper()
+		api, err := ProjectAPIWithOptions(testModule(t, files), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return api
+	}
+
+	prev := load(map[string]string{
+		"a.go": packageSource(`
+// F does things.
+func F(n int) error { return nil }
+
+// T is a type.
+type T struct{ X int }
+
+func (T) M() {}
+
+const C = 1
+`),
+	})
+
+	current := load(map[string]string{
+		"a.go": packageSource(`
+const C = 1
+
+// T is a type.
+type T struct{ X int }
+`),
+		"b.go": packageSource(`
+
+
+
+// F does things.
+func F(n int) error { return nil }
+`),
+		"c.go": packageSource(`func (T) M() {}`),
+	})
+
+	if files := current[0].Files; len(files) != 3 {
+		t.Fatalf("expected the files to be loaded, got %v", files)
+	}
+
+	AssertChanges(t, DiffWithOptions(current, prev, DiffOptions{ReportCosmetic: true}), nil)
+}
+
+func TestSentinelErrorRemoved(t *testing.T) {
+	prev := `
+import "errors"
+
+var ErrNotFound = errors.New("not found")
+
+var ErrTimeout = &TimeoutError{}
+
+var ErrorCount int
+
+var Default error
+
+type TimeoutError struct{}
+
+func (*TimeoutError) Error() string { return "timeout" }
+`
+	current := `
+type TimeoutError struct{}
+
+func (*TimeoutError) Error() string { return "timeout" }
+`
+
+	want := []string{
+		`example.com/m: package-level variable Default: was removed, was a sentinel error, comparisons with it using errors.Is no longer compile`,
+		`example.com/m: package-level variable ErrNotFound: was removed, was a sentinel error, comparisons with it using errors.Is no longer compile`,
+		`example.com/m: package-level variable ErrTimeout: was removed, was a sentinel error, comparisons with it using errors.Is no longer compile`,
+		`example.com/m: package-level variable ErrorCount: was removed`,
+	}
+	AssertChanges(t, diffSources(t, prev, current), want)
+
+	// Variables of type error are still recognized once read back from
+	// JSON.
+	var b bytes.Buffer
+	if err := WriteAPI(&b, sourceAPI(t, prev)); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadAPI(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := Diff(sourceAPI(t, current), read).Strings()
+	if !containsString(changes, want[1]) {
+		t.Errorf("expected %q to be reported, got %q", want[1], changes)
+	}
+}
+
+func TestFieldTypeUnexported(t *testing.T) {
+	prev := `
+import "net"
+
+type Client struct {
+	Conn  net.Conn
+	Pool  []net.Conn
+	Stats *Stats
+}
+
+type Stats struct{}
+
+type internalConn struct{}
+`
+	current := `
+type Client struct {
+	Conn  *internalConn
+	Pool  []internalConn
+	Stats *Stats
+}
+
+type Stats struct{}
+
+type internalConn struct{}
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: struct Client: field "Conn" at position 0: type changed from "net.Conn" to "*example.com/m.internalConn", now refers to unexported type example.com/m.internalConn, which users can't name, field "Pool" at position 1: type changed from "[]net.Conn" to "[]example.com/m.internalConn", now refers to unexported type example.com/m.internalConn, which users can't name`,
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+
+	// Fields that already referred to the unexported type are not noted
+	// again.
+	AssertChanges(t, diffSources(t, current, `
+type Client struct {
+	Conn  internalConn
+	Pool  []internalConn
+	Stats *Stats
+}
+
+type Stats struct{}
+
+type internalConn struct{}
+`), []string{
+		`example.com/m: struct Client: field "Conn" at position 0: type changed from "*example.com/m.internalConn" to "example.com/m.internalConn"`,
+	})
+}
@@ -0,0 +1,98 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// ResultNarrowed is reported along with the type change of a result of an
+// interface method when the new type is more specific than the previous
+// one, e.g. interface{} becoming string. Callers can still use the result
+// as a value of the previous type, but implementations must return the new
+// one.
+type ResultNarrowed struct{}
+
+func (ResultNarrowed) String() string {
+	return "result type is more specific, implementations must return the new type"
+}
+
+// ResultWidened is reported along with the type change of a result of an
+// interface method when the new type is more general than the previous
+// one, e.g. string becoming interface{}. Implementations returning values
+// of the previous type still can, but callers can no longer use the result
+// as a value of the previous type.
+type ResultWidened struct{}
+
+func (ResultWidened) String() string {
+	return "result type is more general, callers can no longer use it as the previous type"
+}
+
+// ArgumentWidened is reported instead of a type change when an argument
+// changes from a concrete type to an interface it implements, e.g. *os.File
+// becoming io.Reader. Callers passing values of the previous type still
+// work, but the function can no longer rely on the behavior specific to
+// that type.
+type ArgumentWidened struct {
+	From types.Type
+	To   types.Type
+}
+
+func (a ArgumentWidened) String() string {
+	return fmt.Sprintf("type widened from %q to %q, which it implements, callers passing the previous type still work", typeString(a.From), typeString(a.To))
+}
+
+// argumentWidened reports whether the type of an argument changed from a
+// concrete type to an interface implemented by it.
+func argumentWidened(prev, current types.Type) bool {
+	_, ok := typeDirection(prev, current).(ResultWidened)
+	return ok
+}
+
+// resultDirections returns the given changes of an interface method with
+// the results whose type changed to a more specific or more general one
+// annotated with ResultNarrowed or ResultWidened.
+func resultDirections(prev, current Func, changes []Change) []Change {
+	var result = make([]Change, len(changes))
+	for i, c := range changes {
+		result[i] = c
+		rc, ok := c.(ResultChanged)
+		if !ok || rc.Pos >= len(prev.Return) || rc.Pos >= len(current.Return) {
+			continue
+		}
+
+		for _, tc := range rc.Changes {
+			if _, ok := tc.(TypeChanged); !ok {
+				continue
+			}
+
+			from, to := prev.Return[rc.Pos].Type, current.Return[rc.Pos].Type
+			if d := typeDirection(from, to); d != nil {
+				rc.Changes = append(rc.Changes[:len(rc.Changes):len(rc.Changes)], d)
+				result[i] = rc
+			}
+			break
+		}
+	}
+	return result
+}
+
+// typeDirection returns ResultNarrowed if values of the current type can be
+// used as values of the previous one, which must be an interface, and
+// ResultWidened if it's the other way around. Named types of different
+// versions are never identical, so only the interfaces whose methods don't
+// refer to them are taken into account.
+func typeDirection(prev, current types.Type) Change {
+	if isSerialized(prev) || isSerialized(current) {
+		return nil
+	}
+
+	_, prevIface := prev.Underlying().(*types.Interface)
+	_, currentIface := current.Underlying().(*types.Interface)
+	switch {
+	case prevIface && !currentIface && types.AssignableTo(current, prev):
+		return ResultNarrowed{}
+	case currentIface && !prevIface && types.AssignableTo(prev, current):
+		return ResultWidened{}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package semverlint
+
+import "testing"
+
+func TestResultDirections(t *testing.T) {
+	general := `
+import "fmt"
+
+type Getter interface {
+	Get() interface{}
+	Name() fmt.Stringer
+	Count() int
+}
+`
+	specific := `
+import "fmt"
+
+type Getter interface {
+	Get() string
+	Name() fmt.Stringer
+	Count() int64
+}
+`
+
+	AssertChanges(t, diffSources(t, general, specific), []string{
+		`example.com/m: interface Getter: method Count: result with type int64 at position 0: type changed from "int" to "int64", existing implementations no longer satisfy the interface, method Get: result with type string at position 0: type changed from "interface{}" to "string", result type is more specific, implementations must return the new type, existing implementations no longer satisfy the interface`,
+	})
+
+	AssertChanges(t, diffSources(t, specific, general), []string{
+		`example.com/m: interface Getter: method Count: result with type int at position 0: type changed from "int64" to "int", existing implementations no longer satisfy the interface, method Get: result with type interface{} at position 0: type changed from "string" to "interface{}", result type is more general, callers can no longer use it as the previous type, existing implementations no longer satisfy the interface`,
+	})
+}
+
+func TestArgumentWidened(t *testing.T) {
+	prev := `
+import "os"
+
+func Copy(r *os.File) error { return nil }
+
+func Parse(s string) {}
+`
+	current := `
+import (
+	"fmt"
+	"io"
+)
+
+func Copy(r io.Reader) error { return nil }
+
+func Parse(s fmt.Stringer) {}
+`
+
+	changes := diffSources(t, prev, current)
+	AssertChanges(t, changes, []string{
+		`example.com/m: function Copy: argument r with type io.Reader at position 0: type widened from "*os.File" to "io.Reader", which it implements, callers passing the previous type still work`,
+		`example.com/m: function Parse: argument s with type fmt.Stringer at position 0: type changed from "string" to "fmt.Stringer"`,
+	})
+
+	// string doesn't implement fmt.Stringer, so Parse still breaks callers.
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+
+	widened := diffSources(t, `
+import "os"
+
+func Copy(r *os.File) {}
+`, `
+import "io"
+
+func Copy(r io.Reader) {}
+`)
+	if bump := Recommend(widened); bump != MinorBump {
+		t.Errorf("expected a minor bump, got %s", bump)
+	}
+
+	// Narrowing an argument breaks the callers passing other types.
+	narrowed := diffSources(t, `
+import "io"
+
+func Copy(r io.Reader) {}
+`, `
+import "os"
+
+func Copy(r *os.File) {}
+`)
+	AssertChanges(t, narrowed, []string{
+		`example.com/m: function Copy: argument r with type *os.File at position 0: type changed from "io.Reader" to "*os.File"`,
+	})
+
+	if bump := Recommend(narrowed); bump != MajorBump {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+}
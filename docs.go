@@ -0,0 +1,93 @@
+package semverlint
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// declDocs returns the doc comments of the top-level declarations in the
+// given files by name. Methods are keyed as "Type.Method", including the
+// methods of interfaces.
+func declDocs(files []*ast.File) map[string]string {
+	var docs = make(map[string]string)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				name := d.Name.Name
+				if d.Recv != nil && len(d.Recv.List) > 0 {
+					name = recvTypeName(d.Recv.List[0].Type) + "." + name
+				}
+				docs[name] = d.Doc.Text()
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						docs[s.Name.Name] = specDoc(d, s.Doc)
+						if iface, ok := s.Type.(*ast.InterfaceType); ok {
+							for _, m := range iface.Methods.List {
+								for _, n := range m.Names {
+									docs[s.Name.Name+"."+n.Name] = m.Doc.Text()
+								}
+							}
+						}
+					case *ast.ValueSpec:
+						for _, n := range s.Names {
+							docs[n.Name] = specDoc(d, s.Doc)
+						}
+					}
+				}
+			}
+		}
+	}
+	return docs
+}
+
+// specDoc returns the doc comment of a spec, which is the one of the whole
+// declaration if it only has one spec without its own doc comment.
+func specDoc(decl *ast.GenDecl, doc *ast.CommentGroup) string {
+	if doc == nil && len(decl.Specs) == 1 {
+		return decl.Doc.Text()
+	}
+	return doc.Text()
+}
+
+// recvTypeName returns the name of the type of a method receiver.
+func recvTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return recvTypeName(e.X)
+	case *ast.ParenExpr:
+		return recvTypeName(e.X)
+	case *ast.IndexExpr:
+		return recvTypeName(e.X)
+	case *ast.IndexListExpr:
+		return recvTypeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	default:
+		return ""
+	}
+}
+
+// packageDoc returns the package doc comment found in the given files.
+func packageDoc(files []*ast.File) string {
+	for _, f := range files {
+		if doc := f.Doc.Text(); doc != "" {
+			return doc
+		}
+	}
+	return ""
+}
+
+// isDeprecated reports whether the given doc comment has a paragraph
+// starting with "Deprecated: ", which is the convention to mark deprecated
+// identifiers and packages.
+func isDeprecated(doc string) bool {
+	for _, paragraph := range strings.Split(doc, "\n\n") {
+		if strings.HasPrefix(strings.TrimSpace(paragraph), "Deprecated: ") {
+			return true
+		}
+	}
+	return false
+}
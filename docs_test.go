@@ -0,0 +1,26 @@
+package semverlint
+
+import "testing"
+
+func TestDocChanged(t *testing.T) {
+	opts := LoadOptions{Docs: true}
+	prev := sourceAPIWithOptions(t, `
+// F does something.
+func F() {}
+`, opts)
+	current := sourceAPIWithOptions(t, `
+// F does something else.
+func F() {}
+`, opts)
+
+	AssertChanges(t, Diff(current, prev), nil)
+
+	changes := DiffWithOptions(current, prev, DiffOptions{ReportCosmetic: true})
+	AssertChanges(t, changes, []string{
+		"example.com/m: function F: doc comment changed",
+	})
+
+	if b := Recommend(changes); b != PatchBump {
+		t.Errorf("expected %s bump, got %s", PatchBump, b)
+	}
+}
@@ -0,0 +1,457 @@
+package semverlint
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// explanation is the template used to explain a kind of change: why it
+// affects the users of the declaration and how they should migrate.
+type explanation struct {
+	Why     string
+	Migrate string
+}
+
+// explanations are the explanations of the kinds of changes by the name of
+// their type. Changes containing other changes are explained by the changes
+// they contain.
+var explanations = map[string]explanation{
+	"Removed": {
+		Why:     "It no longer exists, so any code referring to it no longer compiles.",
+		Migrate: "Replace its uses with whatever supersedes it, if anything, or copy the removed behavior into your own code.",
+	},
+	"Added": {
+		Why:     "It's new. Existing code keeps compiling, unless the new name clashes with a name declared by users, e.g. in packages dot-importing this one.",
+		Migrate: "Nothing is required. Rename your own declarations if they clash with the new one.",
+	},
+	"Renamed": {
+		Why:     "The declaration seems to have been renamed: it was removed and a declaration with a similar name and the same shape was added. Code using the previous name no longer compiles.",
+		Migrate: "Replace the previous name with the new one.",
+	},
+	"NameChanged": {
+		Why:     "The package changed its name while keeping its import path, which breaks the code referring to it by its name without an explicit import name, such as dot-imports and generated code.",
+		Migrate: "Refer to the package by its new name, or import it with the previous name explicitly.",
+	},
+	"KindChanged": {
+		Why:     "The declaration was replaced by a different kind of declaration with the same name, e.g. a variable by a function, which can't be used the same way.",
+		Migrate: "Update the uses of the declaration to the way its new kind is used, e.g. call the function instead of reading the variable.",
+	},
+	"TypeChanged": {
+		Why:     "The type changed, so values of the previous type can no longer be used in its place without a conversion, and code relying on the previous type, such as assignments, comparisons or implementations, may no longer compile.",
+		Migrate: "Use values of the new type, converting the values you have where the types are compatible.",
+	},
+	"TypeArgumentChanged": {
+		Why:     "A type argument of an instantiation of a generic type changed, which makes it a different type, e.g. Result[string] becoming Result[int].",
+		Migrate: "Use values of the type instantiated with the new type arguments.",
+	},
+	"PointerChanged": {
+		Why:     "The type changed between a value of a named type and a pointer to it. Values can't be used as pointers or the other way around.",
+		Migrate: "Take the address of the values you pass, or dereference the pointers, as the new type requires.",
+	},
+	"ResultMayBeNil": {
+		Why:     "A result changed from a value to a pointer, which introduces nil as a possible result that callers didn't have to handle before.",
+		Migrate: "Check the result for nil before using it.",
+	},
+	"ErrorTypeHidden": {
+		Why:     "A result changed from a concrete type to the error interface, so callers can no longer use what is specific to the concrete type, such as its fields.",
+		Migrate: "Use errors.As to get the concrete error back, or rely only on the error interface.",
+	},
+	"ChannelChanged": {
+		Why:     "The type of the variable changed from or to a channel, or between channels, so sends, receives and assignments of the previous type no longer compile.",
+		Migrate: "Use the variable with its new type, e.g. receive from it instead of sending to it.",
+	},
+	"TypeSetChanged": {
+		Why:     "The types allowed by the constraint interface changed, so it may no longer accept the types it's instantiated with or used with.",
+		Migrate: "Instantiate the generic code with types in the new type set.",
+	},
+	"UnexportedTypeReferenced": {
+		Why:     "The type refers to an unexported type, which users can no longer name in their own declarations.",
+		Migrate: "Avoid naming the type: use type inference, e.g. :=, or the exported functions creating its values.",
+	},
+	"PositionChanged": {
+		Why:     "The position of the argument, result or field changed, so callers passing positional arguments, receiving results or writing unkeyed composite literals get the wrong values or no longer compile.",
+		Migrate: "Reorder the arguments, results or fields you use to match the new order, and use keyed composite literals.",
+	},
+	"ValueChanged": {
+		Why:     "The value of the constant changed. Code using it compiles, but behaves differently, and persisted values of the constant no longer match it.",
+		Migrate: "Check the code and the stored data relying on the previous value.",
+	},
+	"IotaShifted": {
+		Why:     "Constants of the same type were shifted by the same amount, which usually means a constant was inserted or removed in the middle of an iota block. Persisted values of the constants are no longer valid.",
+		Migrate: "Migrate stored values to the new numbering, or store the names of the constants instead of their values.",
+	},
+	"EnumValueRemoved": {
+		Why:     "The removed constant is likely one of the values of an enum that switch statements and stored values may rely on.",
+		Migrate: "Remove the cases handling the value and migrate the stored data using it.",
+	},
+	"SentinelErrorRemoved": {
+		Why:     "The removed variable held an error, likely a sentinel error users compare errors with, e.g. errors.Is(err, pkg.ErrNotFound). Those comparisons no longer compile.",
+		Migrate: "Find how the error is reported now, e.g. an error type to use with errors.As, and rethink the code handling it specifically.",
+	},
+	"ErrorReturnAdded": {
+		Why:     "The function now returns an error, so calls using its results as before no longer compile and the new error must be handled.",
+		Migrate: "Receive the error and handle it.",
+	},
+	"ErrorReturnRemoved": {
+		Why:     "The function no longer returns an error, so calls receiving it no longer compile.",
+		Migrate: "Stop receiving the error and remove the code handling it.",
+	},
+	"CleanupReturnAdded": {
+		Why:     "The function now returns a cleanup function, which callers need to receive and call.",
+		Migrate: "Receive the cleanup function and call it, usually with defer, once you're done with the other results.",
+	},
+	"VariadicChanged": {
+		Why:     "The last argument changed between variadic and a slice of the same type, so callers must pass a slice instead of separate values or the other way around.",
+		Migrate: "Pass the values as a slice, e.g. []T{a, b}, or expand the slice you pass with s....",
+	},
+	"ContextArgAdded": {
+		Why:     "The function gained a context.Context as its first argument, so every call to it no longer compiles.",
+		Migrate: "Pass a context as the first argument, the one of the caller if there is one or context.Background() otherwise.",
+	},
+	"ArgumentWidened": {
+		Why:     "The argument changed from a concrete type to an interface it implements. Callers passing values of the previous type still work, but the function can no longer rely on the behavior specific to that type.",
+		Migrate: "Nothing is required, but check the behavior of the function with the values you pass.",
+	},
+	"FunctionalOptionsRefactor": {
+		Why:     "The arguments of the function were collapsed into variadic functional options.",
+		Migrate: "Pass the values of the previous arguments with the corresponding options.",
+	},
+	"PanicAdded": {
+		Why:     "The function panics with a value it didn't panic with before, which may mean it no longer accepts inputs that were valid, even if its signature didn't change.",
+		Migrate: "Check that the values you pass are still valid, or recover from the panic.",
+	},
+	"ParamRenamed": {
+		Why:     "The name of a parameter changed, which is only visible in the documentation.",
+		Migrate: "Nothing is required.",
+	},
+	"ResultRenamed": {
+		Why:     "The name of a result changed, which is only visible in the documentation.",
+		Migrate: "Nothing is required.",
+	},
+	"DocChanged": {
+		Why:     "The doc comment changed, which may document a change of behavior.",
+		Migrate: "Nothing is required, but read the new documentation.",
+	},
+	"WasDeprecated": {
+		Why:     "The removed package was documented as deprecated, so its users were warned in advance.",
+		Migrate: "Use the replacement recommended in its deprecation notice.",
+	},
+	"Unexported": {
+		Why:     "The declaration is still there, but unexported, so it can't be used outside of its package.",
+		Migrate: "Use the exported declarations replacing it, or copy it into your own code.",
+	},
+	"PromotedFieldRemoved": {
+		Why:     "A field promoted from an embedded field is no longer accessible as a field of the struct.",
+		Migrate: "Access the field through the embedded field that still has it, if any.",
+	},
+	"OpacityChanged": {
+		Why:     "The struct went from having exported fields to having none or the other way around. An opaque struct can't be read or set by users, who can only handle it through its functions and methods.",
+		Migrate: "If it became opaque, use its constructor and methods instead of its fields.",
+	},
+	"ConstructorRemoved": {
+		Why:     "The constructor of an opaque struct was removed and no other function or method returns it, so users can no longer create usable values of it: composite literals can't set any of its fields.",
+		Migrate: "Find the new way to obtain values of the struct, or stop depending on it.",
+	},
+	"ConstructorNote": {
+		Why:     "A field was added to a struct that has a constructor, which probably initialises it, so its zero value may not be valid for structs created with a composite literal.",
+		Migrate: "Create the struct with its constructor instead of a composite literal.",
+	},
+	"UnkeyedLiteralBroken": {
+		Why:     "A field was added to the struct, so composite literals of it without field names no longer compile.",
+		Migrate: "Use keyed composite literals, e.g. T{Name: x}.",
+	},
+	"MethodShadowed": {
+		Why:     "The struct defines a method that was promoted from an embedded field, so calls to it no longer reach the embedded field.",
+		Migrate: "Call the method of the embedded field explicitly if you relied on it.",
+	},
+	"ImplementationsBroken": {
+		Why:     "The interface changed in a way that makes its existing implementations no longer satisfy it, e.g. a method was added or its signature changed.",
+		Migrate: "Update your implementations to have the method set of the new interface.",
+	},
+	"InterfaceLost": {
+		Why:     "The type no longer implements a well-known interface of the standard library, so it can't be used anymore where that interface is expected.",
+		Migrate: "Wrap the values in a type implementing the interface, or use the new API of the type.",
+	},
+	"ValueInterfaceLost": {
+		Why:     "Some methods of the type now have pointer receivers, so values of the type no longer implement an interface they used to. Pointers to the type still implement it.",
+		Migrate: "Use pointers to the type where the interface is expected.",
+	},
+	"ReceiverChanged": {
+		Why:     "The receiver of the method changed between a value and a pointer. With a pointer receiver, the method is no longer in the method set of values of the type, which may stop implementing interfaces.",
+		Migrate: "Call the method on addressable values or pointers, and use pointers where an interface with the method is expected.",
+	},
+	"AliasRetargeted": {
+		Why:     "The alias refers to a different type, so values of the previous type can no longer be used as values of the alias.",
+		Migrate: "Use values of the type the alias refers to now.",
+	},
+	"AliasChanged": {
+		Why:     "A defined type became an alias, which changes its method set and makes it identical to the aliased type, or an alias became a defined type, which is no longer identical to the type it referred to.",
+		Migrate: "Convert between the types explicitly, and check the methods you call on them.",
+	},
+	"AliasSubstituted": {
+		Why:     "The type was replaced by an identical one written with different aliases, which only matters if the aliases may diverge in the future.",
+		Migrate: "Nothing is required unless the aliases are expected to change.",
+	},
+	"StdNameShadowed": {
+		Why:     "The new declaration is named after a well-known type of the standard library, which users may confuse with it.",
+		Migrate: "Nothing is required, but qualify the names to avoid mixing both types up.",
+	},
+	"ArityChanged": {
+		Why:     "The number of type parameters of the generic function or type changed, which breaks all its instantiations with explicit type arguments.",
+		Migrate: "Instantiate it with the new number of type arguments.",
+	},
+	"TypeParamsReordered": {
+		Why:     "The type parameters were reordered, e.g. F[K, V] becoming F[V, K], which breaks the instantiations with explicit type arguments and may break the inference of the type arguments.",
+		Migrate: "Reorder the type arguments of the instantiations to match the new order.",
+	},
+	"ConstraintNarrowed": {
+		Why:     "The constraint of the type parameter accepts fewer type arguments than before, so the instantiations with the types it no longer accepts don't compile.",
+		Migrate: "Instantiate it with types satisfying the new constraint.",
+	},
+	"ConstraintWidened": {
+		Why:     "The constraint of the type parameter accepts all the type arguments it accepted before and more.",
+		Migrate: "Nothing is required.",
+	},
+	"TagOptionChanged": {
+		Why:     "An option was added to or removed from the tag of the field, e.g. omitempty in a json tag, which changes how the field is encoded or decoded.",
+		Migrate: "Check the data encoded or decoded with the struct, e.g. zero values that are no longer written.",
+	},
+	"ResultNarrowed": {
+		Why:     "The result of the interface method is more specific than before. Callers can still use it as a value of the previous type, but implementations must return the new one.",
+		Migrate: "Make your implementations return the new type.",
+	},
+	"ResultWidened": {
+		Why:     "The result of the interface method is more general than before. Implementations returning values of the previous type still can, but callers can no longer use the result as a value of the previous type.",
+		Migrate: "Convert the result to the previous type with a type assertion where needed.",
+	},
+	"FoundIn": {
+		Why:     "The change was not found in all the merged sets of changes.",
+		Migrate: "Nothing is required.",
+	},
+}
+
+// variableSeverity are the kinds of changes whose severity depends on the
+// values of the change, which IDs don't include.
+var variableSeverity = map[string]bool{
+	"OpacityChanged":   true,
+	"PositionChanged":  true,
+	"ReceiverChanged":  true,
+	"TagOptionChanged": true,
+	"AliasSubstituted": true,
+}
+
+// Explain returns a detailed explanation of the change with the given ID,
+// as returned by ID, of why it is or isn't breaking and how the users of the
+// declaration should migrate. Since IDs don't include the values of the
+// changes, the explanation is the same for all the changes of the same kind
+// in the same place, and the severity of a few kinds of changes that depend
+// on their values is not known.
+func Explain(id string) (string, error) {
+	parts := strings.SplitN(id, "#", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid change ID %q: expected <package>#<declaration>#<change>", id)
+	}
+
+	pkg, decl, key := parts[0], parts[1], parts[2]
+	c, err := parseChangeKey(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid change ID %q: %s", id, err)
+	}
+
+	d, ok := c.(DeclChange)
+	if !ok {
+		return "", fmt.Errorf("invalid change ID %q: change %q is not a declaration change", id, key)
+	}
+
+	var b strings.Builder
+	if d.Type == PackageType {
+		fmt.Fprintf(&b, "package %s", pkg)
+	} else {
+		fmt.Fprintf(&b, "%s %s of package %s", d.Type, decl, pkg)
+	}
+
+	if len(d.Changes) == 0 {
+		return "", fmt.Errorf("invalid change ID %q: no changes", id)
+	}
+
+	for _, c := range d.Changes {
+		explainChange(&b, c, nil, nil)
+	}
+	return b.String(), nil
+}
+
+// explainChange writes the explanation of the given change, found in the
+// given places of the declaration, to b. Changes containing other changes
+// are explained by the changes they contain. parent is the change
+// containing the given one, if any.
+func explainChange(b *strings.Builder, c Change, where []string, parent Change) {
+	var place string
+	var nested []Change
+	switch c := c.(type) {
+	case ArgumentChanged:
+		place, nested = fmt.Sprintf("argument %d", c.Pos), c.Changes
+	case ResultChanged:
+		place, nested = fmt.Sprintf("result %d", c.Pos), c.Changes
+	case TypeParamChanged:
+		place, nested = fmt.Sprintf("type parameter %d", c.Pos), c.Changes
+	case FieldChanged:
+		place, nested = "field "+c.Name, c.Changes
+	case MethodChanged:
+		place, nested = "method "+c.Name, c.Changes
+	case AliasTargetChanged:
+		place, nested = "aliased type", c.Changes
+	}
+
+	if place != "" {
+		where = append(where[:len(where):len(where)], place)
+		for _, n := range nested {
+			explainChange(b, n, where, c)
+		}
+		return
+	}
+
+	name := reflect.TypeOf(c).Name()
+	severity := SeverityOf(c).String()
+	switch parent.(type) {
+	case ArgumentChanged, ResultChanged:
+		severity = paramSeverity([]Change{c}).String()
+	}
+
+	if variableSeverity[name] {
+		severity = "severity depends on the change"
+	}
+
+	b.WriteString("\n\n")
+	for i := len(where) - 1; i >= 0; i-- {
+		b.WriteString(where[i])
+		if i > 0 {
+			b.WriteString(" of ")
+		} else {
+			b.WriteString(": ")
+		}
+	}
+	fmt.Fprintf(b, "%s (%s)\n", kebabCase(name), severity)
+
+	e := explanations[name]
+	fmt.Fprintf(b, "  Why: %s\n", e.Why)
+	fmt.Fprintf(b, "  Migration: %s", e.Migrate)
+}
+
+// parseChangeKey parses a change key written by changeKey back into a
+// change of the same kind. The values of the changes are not part of the
+// key, so they are left empty.
+func parseChangeKey(key string) (Change, error) {
+	c, rest, err := parseKey(key, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected %q after change", rest)
+	}
+	return c, nil
+}
+
+func parseKey(s string, top bool) (Change, string, error) {
+	end := strings.IndexAny(s, "(),")
+	if end < 0 {
+		end = len(s)
+	}
+
+	name, s := s[:end], s[end:]
+	if name == "" {
+		return nil, "", fmt.Errorf("missing change kind")
+	}
+
+	var nested []Change
+	var hasNested bool
+	if strings.HasPrefix(s, "(") {
+		hasNested = true
+		s = s[1:]
+		for !strings.HasPrefix(s, ")") {
+			c, rest, err := parseKey(s, false)
+			if err != nil {
+				return nil, "", err
+			}
+
+			nested = append(nested, c)
+			s = strings.TrimPrefix(rest, ",")
+			if s == rest && !strings.HasPrefix(s, ")") {
+				return nil, "", fmt.Errorf("unterminated changes of %s", name)
+			}
+		}
+		s = s[1:]
+	}
+
+	if top {
+		for t := VarType; t <= PackageType; t++ {
+			if t.slug() == name {
+				return DeclChange{Type: t, Changes: nested}, s, nil
+			}
+		}
+		return nil, "", fmt.Errorf("unknown declaration kind %q", name)
+	}
+
+	c, err := containerChange(name, nested)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if c != nil {
+		if !hasNested {
+			return nil, "", fmt.Errorf("missing changes of %s", name)
+		}
+		return c, s, nil
+	}
+
+	if hasNested {
+		return nil, "", fmt.Errorf("change %s can't contain other changes", name)
+	}
+
+	for kind, t := range changeKinds {
+		if kebabCase(kind) == name {
+			if _, ok := explanations[kind]; !ok {
+				break
+			}
+			return reflect.Zero(t).Interface().(Change), s, nil
+		}
+	}
+	return nil, "", fmt.Errorf("unknown change kind %q", name)
+}
+
+// containerChange returns the change containing other changes with the
+// given key, or nil if the key is not one of them.
+func containerChange(name string, nested []Change) (Change, error) {
+	if name == "alias-target-changed" {
+		return AliasTargetChanged{Changes: nested}, nil
+	}
+
+	kind, arg, ok := strings.Cut(name, ".")
+	if !ok {
+		return nil, nil
+	}
+
+	switch kind {
+	case "field":
+		return FieldChanged{Name: arg, Changes: nested}, nil
+	case "method":
+		return MethodChanged{Name: arg, Changes: nested}, nil
+	}
+
+	pos, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid position in %q", name)
+	}
+
+	switch kind {
+	case "arg":
+		return ArgumentChanged{Pos: pos, Changes: nested}, nil
+	case "result":
+		return ResultChanged{Pos: pos, Changes: nested}, nil
+	case "tparam":
+		return TypeParamChanged{Pos: pos, Changes: nested}, nil
+	}
+	return nil, fmt.Errorf("unknown change kind %q", name)
+}
@@ -0,0 +1,126 @@
+package semverlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	prev := `
+func F(a int) {}
+
+func G() {}
+`
+	current := `
+func F(a string) {}
+`
+
+	var ids []string
+	for _, pkg := range diffSources(t, prev, current).Report() {
+		for _, c := range pkg.Changes {
+			ids = append(ids, c.ID)
+		}
+	}
+
+	testCases := []struct {
+		id   string
+		want string
+	}{
+		{
+			"example.com/m#G#func(removed)",
+			`function G of package example.com/m
+
+removed (breaking)
+  Why: It no longer exists, so any code referring to it no longer compiles.
+  Migration: Replace its uses with whatever supersedes it, if anything, or copy the removed behavior into your own code.`,
+		},
+		{
+			"example.com/m#F#func(arg.0(type-changed))",
+			`function F of package example.com/m
+
+argument 0: type-changed (breaking)
+  Why: The type changed, so values of the previous type can no longer be used in its place without a conversion, and code relying on the previous type, such as assignments, comparisons or implementations, may no longer compile.
+  Migration: Use values of the new type, converting the values you have where the types are compatible.`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			var found bool
+			for _, id := range ids {
+				found = found || id == tc.id
+			}
+			if !found {
+				t.Errorf("expected ID %q in the report, got %q", tc.id, ids)
+			}
+
+			got, err := Explain(tc.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tc.want {
+				t.Errorf("expected explanation:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestExplainNested(t *testing.T) {
+	got, err := Explain("example.com/m#T#struct(method.Get(result.0(pointer-changed,result-may-be-nil)))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"struct T of package example.com/m",
+		"result 0 of method Get: pointer-changed (breaking)",
+		"result 0 of method Get: result-may-be-nil (breaking)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected explanation to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestExplainInvalid(t *testing.T) {
+	testCases := []struct {
+		id  string
+		err string
+	}{
+		{"example.com/m#G", "expected <package>#<declaration>#<change>"},
+		{"example.com/m#G#nope(removed)", `unknown declaration kind "nope"`},
+		{"example.com/m#G#func(nope)", `unknown change kind "nope"`},
+		{"example.com/m#G#func()", "no changes"},
+		{"example.com/m#G#func(removed", "unterminated changes of func"},
+		{"example.com/m#G#func(removed(added))", "change removed can't contain other changes"},
+		{"example.com/m#G#func(arg.x(removed))", `invalid position in "arg.x"`},
+		{"example.com/m#G#func(removed)x", `unexpected "x" after change`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			_, err := Explain(tc.id)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+// TestExplanations checks that every kind of change that doesn't contain
+// other changes can be explained.
+func TestExplanations(t *testing.T) {
+	for kind := range changeKinds {
+		switch kind {
+		case "DeclChange", "ArgumentChanged", "ResultChanged", "TypeParamChanged",
+			"FieldChanged", "MethodChanged", "AliasTargetChanged":
+			continue
+		}
+
+		e, ok := explanations[kind]
+		if !ok || e.Why == "" || e.Migrate == "" {
+			t.Errorf("expected an explanation of %s", kind)
+		}
+	}
+}
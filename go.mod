@@ -1,9 +1,26 @@
 module github.com/erizocosmico/semverlint
 
-go 1.12
+go 1.26.0
 
 require (
 	github.com/Masterminds/semver v1.5.0
-	golang.org/x/tools v0.0.0-20190920023704-c426260dee6e
+	golang.org/x/mod v0.41.0
+	golang.org/x/tools v0.50.0
 	gopkg.in/src-d/go-git.v4 v4.13.1
 )
+
+require (
+	github.com/emirpasic/gods v1.12.0 // indirect
+	github.com/jbenet/go-context v0.0.0-20150711004518-d14ea06fba99 // indirect
+	github.com/kevinburke/ssh_config v0.0.0-20190725054713-01f96b0aa0cd // indirect
+	github.com/mitchellh/go-homedir v1.1.0 // indirect
+	github.com/sergi/go-diff v1.0.0 // indirect
+	github.com/src-d/gcfg v1.4.0 // indirect
+	github.com/xanzy/ssh-agent v0.2.1 // indirect
+	golang.org/x/crypto v0.57.0 // indirect
+	golang.org/x/net v0.59.0 // indirect
+	golang.org/x/sync v0.23.0 // indirect
+	golang.org/x/sys v0.48.0 // indirect
+	gopkg.in/src-d/go-billy.v4 v4.3.2 // indirect
+	gopkg.in/warnings.v0 v0.1.2 // indirect
+)
@@ -0,0 +1,228 @@
+package semverlint
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// assertGolden fails the test if got is not the content of the golden file
+// with the given name in testdata, or updates the file with -update.
+func assertGolden(t testing.TB, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != string(want) {
+		t.Errorf("output does not match %s:\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
+
+// testModulePath is the path of the modules written by testModule.
+const testModulePath = "example.com/m"
+
+// writeFiles writes the given files, by their path relative to dir using
+// forward slashes, to dir.
+func writeFiles(t testing.TB, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testModule writes a module with the given files to a temporary directory
+// and returns its path. A go.mod for testModulePath is written unless the
+// files have one.
+func testModule(t testing.TB, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if _, ok := files["go.mod"]; !ok {
+		writeFiles(t, dir, map[string]string{
+			"go.mod": "module " + testModulePath + "\n\ngo 1.26\n",
+		})
+	}
+
+	writeFiles(t, dir, files)
+	return dir
+}
+
+// moduleAPI returns the API of a module with the given files.
+func moduleAPI(t testing.TB, files map[string]string) API {
+	t.Helper()
+
+	api, err := ProjectAPI(testModule(t, files))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+// packageSource returns the source of a file of package m with the given
+// declarations.
+func packageSource(decls string) string {
+	return "package m\n\n" + strings.TrimSpace(decls) + "\n"
+}
+
+// sourceAPI returns the API of a module whose root package has the given
+// declarations.
+func sourceAPI(t testing.TB, decls string) API {
+	t.Helper()
+	return sourceAPIWithOptions(t, decls, LoadOptions{})
+}
+
+// sourceAPIWithOptions is like sourceAPI, but loads the API with the given
+// options.
+func sourceAPIWithOptions(t testing.TB, decls string, opts LoadOptions) API {
+	t.Helper()
+
+	dir := testModule(t, map[string]string{"m.go": packageSource(decls)})
+	api, err := ProjectAPIWithOptions(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+// diffSources returns the changes between the APIs of the root package of a
+// module with the given previous and current declarations.
+func diffSources(t testing.TB, prev, current string) APIChanges {
+	t.Helper()
+	return diffSourcesWithOptions(t, prev, current, DiffOptions{})
+}
+
+// diffSourcesWithOptions is like diffSources, but compares the APIs with the
+// given options.
+func diffSourcesWithOptions(t testing.TB, prev, current string, opts DiffOptions) APIChanges {
+	t.Helper()
+	return DiffWithOptions(sourceAPI(t, current), sourceAPI(t, prev), opts)
+}
+
+// testRepo is a git repository with a module used as a fixture.
+type testRepo struct {
+	t    testing.TB
+	dir  string
+	repo *git.Repository
+	// commits is the number of commits made, used to give them increasing
+	// dates.
+	commits int
+}
+
+// newTestRepo creates an empty repository in a temporary directory.
+func newTestRepo(t testing.TB) *testRepo {
+	t.Helper()
+
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testRepo{t: t, dir: dir, repo: r}
+}
+
+// commit writes the given files to the working tree, removing the ones
+// whose content is empty, and commits them. A go.mod for testModulePath is
+// written in the first commit unless the files have one.
+func (r *testRepo) commit(files map[string]string) plumbing.Hash {
+	r.t.Helper()
+
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+
+	if _, ok := files["go.mod"]; !ok && r.commits == 0 {
+		files["go.mod"] = "module " + testModulePath + "\n\ngo 1.26\n"
+	}
+
+	for name, content := range files {
+		if content == "" {
+			if _, err := wt.Remove(name); err != nil {
+				r.t.Fatal(err)
+			}
+			continue
+		}
+
+		writeFiles(r.t, r.dir, map[string]string{name: content})
+		if _, err := wt.Add(name); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+
+	r.commits++
+	sig := &object.Signature{
+		Name:  "test",
+		Email: "test@example.com",
+		When:  time.Date(2020, 1, 1, 0, 0, r.commits, 0, time.UTC),
+	}
+
+	h, err := wt.Commit("commit", &git.CommitOptions{Author: sig, Committer: sig})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return h
+}
+
+// tag creates a lightweight tag with the given name for the given commit.
+func (r *testRepo) tag(name string, h plumbing.Hash) {
+	r.t.Helper()
+
+	ref := plumbing.NewHashReference(plumbing.NewTagReferenceName(name), h)
+	if err := r.repo.Storer.SetReference(ref); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// branch creates a branch with the given name pointing to the given commit.
+func (r *testRepo) branch(name string, h plumbing.Hash) {
+	r.t.Helper()
+
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(name), h)
+	if err := r.repo.Storer.SetReference(ref); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// checkout checks out the branch with the given name, so the next commits
+// are made on it.
+func (r *testRepo) checkout(name string) {
+	r.t.Helper()
+
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+
+	err = wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(name)})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+}
@@ -0,0 +1,154 @@
+package semverlint
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/go-git.v4"
+)
+
+// ChangesSince returns the changes made to the API of the project at the
+// given path since the given baseline version, which must be one of its
+// versions. Changes are cumulative, that is, they are the result of diffing
+// the API at the baseline against the API at HEAD, so a declaration added
+// in one release and removed in a later one is not reported.
+func ChangesSince(path, baseline string) (APIChanges, error) {
+	versions, err := Versions(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var base, head *Version
+	for i, v := range versions {
+		switch v.Name {
+		case headVersion:
+			head = &versions[i]
+		case baseline:
+			base = &versions[i]
+		}
+	}
+
+	if base == nil {
+		return nil, fmt.Errorf("version %q not found", baseline)
+	}
+
+	prev, err := VersionAPI(path, *base)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get API of version %s: %s", base.Name, err)
+	}
+
+	current, err := VersionAPI(path, *head)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get API of HEAD: %s", err)
+	}
+
+	return Diff(current, prev), nil
+}
+
+// DiffRefs returns the changes made to the API of the repository at the
+// given path between two revisions, which can be branches, tags or any
+// other revision understood by git, e.g. the branch of a pull request and
+// the branch it will be merged into.
+func DiffRefs(path, baseRef, headRef string) (APIChanges, error) {
+	base, err := ResolveVersion(path, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := ResolveVersion(path, headRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffVersions(path, base, head)
+}
+
+// DiffMergeBase returns the changes made to the API of the repository at the
+// given path in headRef since it diverged from baseRef, that is, between
+// the merge base of both revisions and headRef, e.g. the changes of a pull
+// request without the ones made to the branch it will be merged into since
+// it was created. If there are several merge bases, any of them is used.
+func DiffMergeBase(path, baseRef, headRef string) (APIChanges, error) {
+	base, err := ResolveVersion(path, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := ResolveVersion(path, headRef)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open repository: %s", err)
+	}
+
+	baseCommit, err := r.CommitObject(base.Commit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get commit of %s: %s", base.Name, err)
+	}
+
+	headCommit, err := r.CommitObject(head.Commit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get commit of %s: %s", head.Name, err)
+	}
+
+	bases, err := baseCommit.MergeBase(headCommit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get merge base of %s and %s: %s", base.Name, head.Name, err)
+	}
+
+	if len(bases) == 0 {
+		return nil, fmt.Errorf("%s and %s have no common history", base.Name, head.Name)
+	}
+
+	mergeBase := Version{
+		Name:   fmt.Sprintf("merge base of %s and %s", base.Name, head.Name),
+		Commit: bases[0].Hash,
+	}
+	return diffVersions(path, mergeBase, head)
+}
+
+// DiffRefsCached is like DiffRefs, but the changes are read from the given
+// cache if they were already computed for the same pair of commits, and
+// stored in it otherwise. Failing to store them, e.g. because the cache
+// directory is read-only, is only a warning.
+func DiffRefsCached(path, baseRef, headRef string, cache DiffCache) (APIChanges, error) {
+	base, err := ResolveVersion(path, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := ResolveVersion(path, headRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes, ok := cache.get(base.Commit, head.Commit); ok {
+		return changes, nil
+	}
+
+	changes, err := diffVersions(path, base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cache.put(base.Commit, head.Commit, changes); err != nil {
+		cache.warn("unable to cache changes between %s and %s: %s", base.Name, head.Name, err)
+	}
+	return changes, nil
+}
+
+func diffVersions(path string, base, head Version) (APIChanges, error) {
+	prev, err := VersionAPI(path, base)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get API of %s: %s", base.Name, err)
+	}
+
+	current, err := VersionAPI(path, head)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get API of %s: %s", head.Name, err)
+	}
+
+	return Diff(current, prev), nil
+}
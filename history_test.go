@@ -0,0 +1,110 @@
+package semverlint
+
+import "testing"
+
+func TestChangesSince(t *testing.T) {
+	r := newTestRepo(t)
+	r.tag("v1.0.0", r.commit(map[string]string{"m.go": packageSource(`func F() {}`)}))
+	r.tag("v1.1.0", r.commit(map[string]string{"m.go": packageSource(`
+func F() {}
+
+func G() {}
+`)}))
+	r.tag("v2.0.0", r.commit(map[string]string{"m.go": packageSource(`
+func F() {}
+
+func H() {}
+`)}))
+
+	// Changes are cumulative, so G, added and removed after v1.0.0, is not
+	// reported.
+	changes, err := ChangesSince(r.dir, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertChanges(t, changes, []string{
+		"example.com/m: function H: was added",
+	})
+
+	changes, err = ChangesSince(r.dir, "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertChanges(t, changes, []string{
+		"example.com/m: function G: was removed",
+		"example.com/m: function H: was added",
+	})
+
+	if _, err := ChangesSince(r.dir, "v0.1.0"); err == nil {
+		t.Error("expected an error for an unknown baseline")
+	}
+}
+
+func TestDiffRefs(t *testing.T) {
+	r := newTestRepo(t)
+	r.branch("main", r.commit(map[string]string{"m.go": packageSource(`func F() {}`)}))
+	r.branch("feature", r.commit(map[string]string{"m.go": packageSource(`func F(n int) {}`)}))
+
+	changes, err := DiffRefs(r.dir, "main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertChanges(t, changes, []string{
+		"example.com/m: function F: argument n with type int at position 0: was added",
+	})
+
+	if _, err := DiffRefs(r.dir, "main", "unknown"); err == nil {
+		t.Error("expected an error for an unknown branch")
+	}
+}
+
+func TestDiffMergeBase(t *testing.T) {
+	r := newTestRepo(t)
+	base := r.commit(map[string]string{"m.go": packageSource(`func F() {}`)})
+	r.branch("feature", base)
+
+	// The target branch moves on after the feature branch is created.
+	r.commit(map[string]string{"m.go": packageSource(`
+func F() {}
+
+func G() {}
+`)})
+
+	r.checkout("feature")
+	r.commit(map[string]string{"m.go": packageSource(`func F(n int) {}`)})
+
+	changes, err := DiffMergeBase(r.dir, "master", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertChanges(t, changes, []string{
+		"example.com/m: function F: argument n with type int at position 0: was added",
+	})
+
+	// Diffing against the tip of the target branch attributes its changes
+	// to the feature branch.
+	changes, err = DiffRefs(r.dir, "master", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertChanges(t, changes, []string{
+		"example.com/m: function F: argument n with type int at position 0: was added",
+		"example.com/m: function G: was removed",
+	})
+
+	// The other way around, only the changes of the target branch are
+	// reported.
+	changes, err = DiffMergeBase(r.dir, "feature", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertChanges(t, changes, []string{
+		"example.com/m: function G: was added",
+	})
+}
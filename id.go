@@ -0,0 +1,74 @@
+package semverlint
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ID returns a stable identifier for the given change of the declaration
+// with the given name in the package with the given path, which can be used
+// to deduplicate, suppress or correlate changes across runs. The format is
+//
+//	<package path>#<declaration>#<change>
+//
+// where <change> is the name of the kind of change in kebab case, e.g.
+// removed or type-changed. Changes containing other changes are written as
+// their kind followed by the changes they contain between parentheses, and
+// the kind of a declaration change is the kind of declaration, e.g.
+//
+//	github.com/me/mod/pkg#Foo#func(arg.0(type-changed),error-return-added)
+//
+// The values of the changes, such as the previous and current types, are not
+// part of the ID.
+func ID(pkg, decl string, c Change) string {
+	return pkg + "#" + decl + "#" + changeKey(c)
+}
+
+func changeKey(c Change) string {
+	switch c := c.(type) {
+	case DeclChange:
+		return c.Type.slug() + nestedKeys(c.Changes)
+	case ArgumentChanged:
+		return fmt.Sprintf("arg.%d%s", c.Pos, nestedKeys(c.Changes))
+	case ResultChanged:
+		return fmt.Sprintf("result.%d%s", c.Pos, nestedKeys(c.Changes))
+	case TypeParamChanged:
+		return fmt.Sprintf("tparam.%d%s", c.Pos, nestedKeys(c.Changes))
+	case FieldChanged:
+		return "field." + c.Name + nestedKeys(c.Changes)
+	case MethodChanged:
+		return "method." + c.Name + nestedKeys(c.Changes)
+	case AliasTargetChanged:
+		return "alias-target-changed" + nestedKeys(c.Changes)
+	default:
+		return kebabCase(reflect.TypeOf(c).Name())
+	}
+}
+
+func nestedKeys(cs []Change) string {
+	var keys = make([]string, 0, len(cs))
+	for _, c := range cs {
+		// Where a merged change was found does not change what it is.
+		if _, ok := c.(FoundIn); ok {
+			continue
+		}
+		keys = append(keys, changeKey(c))
+	}
+	return "(" + strings.Join(keys, ",") + ")"
+}
+
+func kebabCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteRune('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package semverlint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestID(t *testing.T) {
+	prev := `
+func F(a int) {}
+
+func G() {}
+
+type T struct{ X int }
+`
+	current := `
+func F(a string) error { return nil }
+
+func H() {}
+
+type T struct{ X, Y int }
+`
+
+	ids := func() []string {
+		var result []string
+		for _, pkg := range diffSources(t, prev, current) {
+			for _, c := range pkg.Changes {
+				d := c.(DeclChange)
+				result = append(result, ID(pkg.Path, d.Name, d))
+			}
+		}
+		return result
+	}
+
+	first := ids()
+	want := []string{
+		"example.com/m#F#func(arg.0(type-changed),error-return-added)",
+		"example.com/m#G#func(removed)",
+		"example.com/m#H#func(added)",
+		"example.com/m#T#struct(field.Y(added))",
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("expected IDs %q, got %q", want, first)
+	}
+
+	if second := ids(); !reflect.DeepEqual(first, second) {
+		t.Errorf("expected IDs to be stable across runs, got %q and %q", first, second)
+	}
+
+	var seen = make(map[string]struct{})
+	for _, id := range first {
+		if _, ok := seen[id]; ok {
+			t.Errorf("duplicate ID %q", id)
+		}
+		seen[id] = struct{}{}
+	}
+}
@@ -0,0 +1,38 @@
+package semverlint
+
+import "sort"
+
+// Symbol is an exported declaration of a package.
+type Symbol struct {
+	Package string
+	Name    string
+	Kind    DeclType
+	// Signature is the rendered declaration, as in the Before and After
+	// fields of DeclChange.
+	Signature string
+}
+
+// Inventory returns the exported top-level declarations of the given API
+// sorted by package path and name. Methods are part of the signature of the
+// types they belong to.
+func Inventory(api API) []Symbol {
+	var result []Symbol
+	for _, pkg := range api {
+		for name, kind := range declNames(pkg) {
+			result = append(result, Symbol{
+				Package:   pkg.Path,
+				Name:      name,
+				Kind:      kind,
+				Signature: declSignature(pkg, kind, name),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Package != result[j].Package {
+			return result[i].Package < result[j].Package
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
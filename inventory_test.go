@@ -0,0 +1,63 @@
+package semverlint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestInventory(t *testing.T) {
+	api := moduleAPI(t, map[string]string{
+		"m.go": packageSource(`
+type S struct {
+	X int
+	y int
+}
+
+func (S) M() {}
+
+func (*S) PM(int) error { return nil }
+
+type I interface{ M() }
+
+type ID int
+
+type Alias = map[string]int
+
+type G[T any] struct{ V T }
+
+const C = 1
+
+const (
+	A ID = iota
+	B
+)
+
+var V, W string
+
+var unexportedVar int
+
+func F(a int, b ...string) (int, error) { return 0, nil }
+
+func unexported() {}
+`),
+		"b/b.go": "package b\n\nfunc F() {}\n",
+		"a/a.go": "package a\n\nvar Z int\n",
+	})
+
+	symbols := Inventory(api)
+
+	var sb strings.Builder
+	var seen = make(map[string]bool)
+	for _, s := range symbols {
+		key := s.Package + "." + s.Name
+		if seen[key] {
+			t.Errorf("symbol %s listed more than once", key)
+		}
+		seen[key] = true
+
+		fmt.Fprintf(&sb, "%s %s %s\n%s\n\n", s.Package, s.Kind, s.Name, s.Signature)
+	}
+
+	assertGolden(t, "inventory.golden", sb.String())
+}
@@ -0,0 +1,113 @@
+package semverlint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FoundIn is attached to the merged changes that were not found in all the
+// merged sets of changes, and lists the sets they were found in. It's not
+// part of the ID of the change.
+type FoundIn struct {
+	Sources []string
+}
+
+func (f FoundIn) String() string {
+	return fmt.Sprintf("only applies to %s", strings.Join(f.Sources, ", "))
+}
+
+// Merge combines several sets of changes, e.g. the changes of different
+// modules or platforms, into a single one without duplicates. Changes are
+// duplicates if they have the same ID and description. Packages and changes
+// are in the order they are first found. Declaration changes that are not
+// found in all the sets are annotated with a FoundIn change with the
+// positions of the sets they were found in, e.g. "set 0".
+func Merge(sets ...APIChanges) APIChanges {
+	var sources = make([]string, len(sets))
+	for i := range sets {
+		sources[i] = fmt.Sprintf("set %d", i)
+	}
+	return merge(sets, sources)
+}
+
+// MergePlatforms is like Merge for the changes of each platform returned by
+// DiffAllPlatforms, annotating the changes with the platforms they were
+// found in. Sets are merged in the order of their platforms.
+func MergePlatforms(changes map[Platform]APIChanges) APIChanges {
+	var platforms = make([]Platform, 0, len(changes))
+	for p := range changes {
+		platforms = append(platforms, p)
+	}
+	sort.Slice(platforms, func(i, j int) bool {
+		return platforms[i].String() < platforms[j].String()
+	})
+
+	var sets = make([]APIChanges, len(platforms))
+	var sources = make([]string, len(platforms))
+	for i, p := range platforms {
+		sets[i], sources[i] = changes[p], p.String()
+	}
+	return merge(sets, sources)
+}
+
+func merge(sets []APIChanges, sources []string) APIChanges {
+	type found struct {
+		change  Change
+		sources []string
+	}
+
+	type pkgChanges struct {
+		name    string
+		changes []*found
+		byKey   map[string]*found
+	}
+
+	var paths []string
+	var pkgs = make(map[string]*pkgChanges)
+	for i, set := range sets {
+		for _, pkg := range set {
+			p, ok := pkgs[pkg.Path]
+			if !ok {
+				p = &pkgChanges{name: pkg.Name, byKey: make(map[string]*found)}
+				pkgs[pkg.Path] = p
+				paths = append(paths, pkg.Path)
+			}
+
+			for _, c := range pkg.Changes {
+				var decl string
+				if d, ok := c.(DeclChange); ok {
+					decl = d.Name
+				}
+
+				key := ID(pkg.Path, decl, c) + "\n" + c.String()
+				f, ok := p.byKey[key]
+				if !ok {
+					f = &found{change: c}
+					p.byKey[key] = f
+					p.changes = append(p.changes, f)
+				}
+
+				// The same change may be reported more than once in a set.
+				if n := len(f.sources); n == 0 || f.sources[n-1] != sources[i] {
+					f.sources = append(f.sources, sources[i])
+				}
+			}
+		}
+	}
+
+	var result = make(APIChanges, len(paths))
+	for i, path := range paths {
+		p := pkgs[path]
+		result[i] = PackageChanges{Name: p.name, Path: path}
+		for _, f := range p.changes {
+			c := f.change
+			if d, ok := c.(DeclChange); ok && len(f.sources) < len(sources) {
+				d.Changes = append(d.Changes[:len(d.Changes):len(d.Changes)], FoundIn{f.sources})
+				c = d
+			}
+			result[i].Changes = append(result[i].Changes, c)
+		}
+	}
+	return result
+}
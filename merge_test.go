@@ -0,0 +1,69 @@
+package semverlint
+
+import (
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	removed := NewDeclChange("F", FuncType, Removed{})
+	changed := NewDeclChange("V", VarType, TypeChanged{
+		From: types.Typ[types.Int],
+		To:   types.Typ[types.Int64],
+	})
+
+	a := APIChanges{
+		NewPackageChanges("a", "example.com/m/a", removed, changed),
+		NewPackageChanges("b", "example.com/m/b", NewDeclChange("B", FuncType, Added{})),
+	}
+
+	b := APIChanges{
+		// The same change reported twice in a set is only merged once.
+		NewPackageChanges("a", "example.com/m/a", removed, removed),
+		NewPackageChanges("c", "example.com/m/c", NewDeclChange("C", PackageType, Removed{})),
+	}
+
+	merged := Merge(a, b)
+	AssertChanges(t, merged, []string{
+		`example.com/m/a: function F: was removed`,
+		`example.com/m/a: package-level variable V: type changed from "int" to "int64", only applies to set 0`,
+		`example.com/m/b: function B: was added, only applies to set 0`,
+		`example.com/m/c: package C: was removed, only applies to set 1`,
+	})
+
+	var paths []string
+	for _, pkg := range merged {
+		paths = append(paths, pkg.Path)
+	}
+
+	if want := []string{"example.com/m/a", "example.com/m/b", "example.com/m/c"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected packages in order %v, got %v", want, paths)
+	}
+
+	// Merging must not modify the merged sets.
+	AssertChanges(t, a, []string{
+		`example.com/m/a: function F: was removed`,
+		`example.com/m/a: package-level variable V: type changed from "int" to "int64"`,
+		`example.com/m/b: function B: was added`,
+	})
+
+	AssertChanges(t, Merge(a, a), a.Strings())
+	AssertChanges(t, Merge(), nil)
+}
+
+func TestMergePlatforms(t *testing.T) {
+	removed := NewDeclChange("F", FuncType, Removed{})
+	added := NewDeclChange("G", FuncType, Added{})
+
+	merged := MergePlatforms(map[Platform]APIChanges{
+		{"linux", "amd64"}:   {NewPackageChanges("m", "example.com/m", removed, added)},
+		{"darwin", "arm64"}:  {NewPackageChanges("m", "example.com/m", removed)},
+		{"windows", "amd64"}: {NewPackageChanges("m", "example.com/m", removed, added)},
+	})
+
+	AssertChanges(t, merged, []string{
+		`example.com/m: function F: was removed`,
+		`example.com/m: function G: was added, only applies to linux/amd64, windows/amd64`,
+	})
+}
@@ -0,0 +1,33 @@
+package semverlint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ErrNoModule is returned by ModulePath when the directory has no go.mod
+// file, e.g. GOPATH projects.
+var ErrNoModule = errors.New("no go.mod file found")
+
+// ModulePath returns the path of the module declared in the go.mod file of
+// the given directory.
+func ModulePath(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if os.IsNotExist(err) {
+		return "", ErrNoModule
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unable to read go.mod: %s", err)
+	}
+
+	path := modfile.ModulePath(data)
+	if path == "" {
+		return "", fmt.Errorf("no module path declared in %s", filepath.Join(dir, "go.mod"))
+	}
+	return path, nil
+}
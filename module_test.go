@@ -0,0 +1,30 @@
+package semverlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestModulePath(t *testing.T) {
+	dir := testModule(t, map[string]string{
+		"go.mod": "// Comments are allowed.\nmodule \"github.com/me/mod/v2\"\n\ngo 1.26\n\nrequire example.com/dep v1.0.0\n",
+	})
+
+	path, err := ModulePath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "github.com/me/mod/v2"; path != want {
+		t.Errorf("expected module path %q, got %q", want, path)
+	}
+
+	if _, err := ModulePath(t.TempDir()); err != ErrNoModule {
+		t.Errorf("expected ErrNoModule without go.mod, got %v", err)
+	}
+
+	dir = testModule(t, map[string]string{"go.mod": "go 1.26\n"})
+	if _, err := ModulePath(dir); err == nil || !strings.Contains(err.Error(), "no module path declared") {
+		t.Errorf("expected an error without module path, got %v", err)
+	}
+}
@@ -2,29 +2,45 @@ package semverlint
 
 import "go/types"
 
-// Package with all its exposed members.
+// Package with all its exposed members. Doc comments of the members are only
+// available when they're explicitly requested while loading the API.
 type Package struct {
-	Name       string
-	Path       string
+	Name string
+	Path string
+	// Files of the package relative to the root of the project, only
+	// available when they're explicitly requested while loading the API.
+	// They are not part of the API and are never compared, so moving
+	// declarations between files of a package doesn't change it.
+	Files      []string
 	Vars       []Var
 	Consts     []Const
 	Funcs      []Func
 	Structs    []Struct
 	Interfaces []Interface
 	Types      []TypeDef
+	// Doc is the package doc comment, only available when docs are
+	// explicitly requested while loading the API.
+	Doc string
 }
 
-// TypeDef is a type definition of the type `type A B` or `type A = B`.
+// TypeDef is a type definition of the type `type A B` or `type A = B`. For
+// aliases, Type is the aliased type.
 type TypeDef struct {
-	Name  string
-	Type  types.Type
-	Alias bool
+	Name string
+	// TypeParams of the type, if it's generic.
+	TypeParams []TypeParam
+	Type       types.Type
+	Alias      bool
+	Doc        string
+	// Methods of the type, which aliases don't have.
+	Methods []Func
 }
 
 // Var is an exposed variable.
 type Var struct {
 	Name string
 	Type types.Type
+	Doc  string
 }
 
 // Const is an exposed constant.
@@ -32,30 +48,81 @@ type Const struct {
 	Name  string
 	Type  types.Type
 	Value string
+	Doc   string
 }
 
 // Func or method exposed.
 type Func struct {
-	Name   string
-	Args   []types.Type
-	Return []types.Type
+	Name string
+	// TypeParams of the function or, for methods, of the receiver.
+	TypeParams []TypeParam
+	Args       []Param
+	// Variadic reports whether the last argument is variadic, in which
+	// case its type is a slice.
+	Variadic bool
+	Return   []Param
+	Doc      string
+	// Embedded is the name of the embedded field a method of a struct is
+	// promoted from, if any.
+	Embedded string
+	// PointerReceiver reports whether a method of a type is only in the
+	// method set of pointers to the type, not in the one of its values.
+	PointerReceiver bool
+	// Panics are the values the function panics with in its body, as
+	// written in the source, only available when they're explicitly
+	// requested while loading the API.
+	Panics []string
+}
+
+// TypeParam is a type parameter of a generic function or type.
+type TypeParam struct {
+	Name       string
+	Constraint types.Type
+}
+
+// Param is an argument or a result of a function. Name is empty for unnamed
+// parameters.
+type Param struct {
+	Name string
+	Type types.Type
 }
 
 // Interface exposed.
 type Interface struct {
-	Name    string
-	Methods []Func
+	Name string
+	// TypeParams of the interface, if it's generic.
+	TypeParams []TypeParam
+	Methods    []Func
+	// TypeSet contains the embedded elements restricting the types that
+	// satisfy the interface, such as unions in constraint interfaces.
+	TypeSet []types.Type
+	Doc     string
 }
 
 // Struct exposed.
 type Struct struct {
-	Name    string
-	Fields  []Field
-	Methods []Func
+	Name string
+	// TypeParams of the struct, if it's generic.
+	TypeParams []TypeParam
+	Fields     []Field
+	// PromotedFields are the exported fields promoted from embedded
+	// fields, which are accessible as fields of the struct.
+	PromotedFields []PromotedField
+	Methods        []Func
+	Doc            string
+}
+
+// PromotedField is a field promoted from an embedded field of a struct.
+type PromotedField struct {
+	Name string
+	// Embedded is the name of the embedded field of the struct it's
+	// promoted from.
+	Embedded string
 }
 
 // Field exposed in a struct.
 type Field struct {
 	Name string
 	Type types.Type
+	Tag  string
 }
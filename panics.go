@@ -0,0 +1,97 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+)
+
+// PanicAdded is an advisory note reported when a function or method panics
+// with a value it didn't panic with before, which may mean it no longer
+// accepts inputs that were valid, even if its signature didn't change. It
+// requires loading both APIs with LoadOptions.Panics.
+type PanicAdded struct {
+	Value string
+}
+
+func (p PanicAdded) String() string {
+	return fmt.Sprintf("now panics with %s, behavior may have changed", p.Value)
+}
+
+// declPanics returns the arguments of the panic calls in the bodies of the
+// functions and methods declared in the given files, as written in the
+// source, by name. Methods are keyed as "Type.Method". Calls inside
+// function literals are ignored, since they may never run as part of the
+// function.
+func declPanics(files []*ast.File) map[string][]string {
+	var panics = make(map[string][]string)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.FuncDecl)
+			if !ok || d.Body == nil {
+				continue
+			}
+
+			name := d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				name = recvTypeName(d.Recv.List[0].Type) + "." + name
+			}
+
+			ast.Inspect(d.Body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncLit:
+					return false
+				case *ast.CallExpr:
+					if id, ok := n.Fun.(*ast.Ident); ok && id.Name == "panic" && len(n.Args) == 1 {
+						panics[name] = append(panics[name], types.ExprString(n.Args[0]))
+					}
+				}
+				return true
+			})
+		}
+	}
+	return panics
+}
+
+// withPanics sets the panics of the functions and methods of the package
+// as returned by declPanics. Promoted methods are not set, since they are
+// declared by other types.
+func withPanics(pkg *Package, panics map[string][]string) {
+	for i, f := range pkg.Funcs {
+		pkg.Funcs[i].Panics = panics[f.Name]
+	}
+
+	setMethods := func(typ string, methods []Func) {
+		for i, m := range methods {
+			if m.Embedded == "" {
+				methods[i].Panics = panics[typ+"."+m.Name]
+			}
+		}
+	}
+
+	for _, s := range pkg.Structs {
+		setMethods(s.Name, s.Methods)
+	}
+
+	for _, t := range pkg.Types {
+		setMethods(t.Name, t.Methods)
+	}
+}
+
+// panicsDiff returns the values a function panics with that it didn't
+// panic with before.
+func panicsDiff(prev, current []string) []Change {
+	var prevPanics = make(map[string]struct{}, len(prev))
+	for _, p := range prev {
+		prevPanics[p] = struct{}{}
+	}
+
+	var changes []Change
+	for _, p := range current {
+		if _, ok := prevPanics[p]; !ok {
+			prevPanics[p] = struct{}{}
+			changes = append(changes, PanicAdded{p})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,63 @@
+package semverlint
+
+import "testing"
+
+func TestPanicAdded(t *testing.T) {
+	prev := `
+func Parse(s string) int {
+	if s == "" {
+		panic("empty input")
+	}
+	return 0
+}
+
+type T struct{}
+
+func (T) Do(n int) {}
+
+func Go() {}
+`
+	current := `
+func Parse(s string) int {
+	if s == "" {
+		panic("empty input")
+	}
+	if len(s) > 10 {
+		panic(errTooLong)
+	}
+	return 0
+}
+
+var errTooLong = errors.New("too long")
+
+type T struct{}
+
+func (T) Do(n int) {
+	if n < 0 {
+		panic(fmt.Sprintf("negative: %d", n))
+	}
+}
+
+func Go() {
+	go func() { panic("in a goroutine") }()
+}
+`
+	imports := "import (\n\t\"errors\"\n\t\"fmt\"\n)\n"
+
+	load := func(src string, panics bool) API {
+		return sourceAPIWithOptions(t, src, LoadOptions{Panics: panics})
+	}
+
+	changes := Diff(load(imports+current, true), load(prev, true))
+	AssertChanges(t, changes, []string{
+		`example.com/m: function Parse: now panics with errTooLong, behavior may have changed`,
+		`example.com/m: struct T: method Do: now panics with fmt.Sprintf("negative: %d", n), behavior may have changed`,
+	})
+
+	if bump := Recommend(changes); bump != PatchBump {
+		t.Errorf("expected the notes to be advisory, got a %s bump", bump)
+	}
+
+	// Panics are only compared if they were loaded.
+	AssertChanges(t, Diff(load(imports+current, false), load(prev, false)), nil)
+}
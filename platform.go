@@ -0,0 +1,83 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+)
+
+// Platform is a target operating system and architecture.
+type Platform struct {
+	GOOS   string
+	GOARCH string
+}
+
+func (p Platform) String() string {
+	return p.GOOS + "/" + p.GOARCH
+}
+
+// env returns the current environment targeting the platform.
+func (p Platform) env() []string {
+	return append(os.Environ(), "GOOS="+p.GOOS, "GOARCH="+p.GOARCH)
+}
+
+// commonPlatforms are the platforms checked to find the files of a package
+// that are excluded by build constraints in the current environment but
+// are part of it in other platforms: the first class ports of Go and a few
+// other popular ones.
+var commonPlatforms = []Platform{
+	{"linux", "amd64"},
+	{"linux", "386"},
+	{"linux", "arm"},
+	{"linux", "arm64"},
+	{"darwin", "amd64"},
+	{"darwin", "arm64"},
+	{"windows", "amd64"},
+	{"windows", "386"},
+	{"windows", "arm64"},
+	{"freebsd", "amd64"},
+	{"js", "wasm"},
+	{"wasip1", "wasm"},
+}
+
+// otherPlatformFiles returns the given Go files excluded by build
+// constraints that are part of their package in any of the common
+// platforms, so they may contain API that is missing from the one loaded.
+func otherPlatformFiles(ignored []string) []string {
+	var result []string
+	for _, f := range ignored {
+		dir, name := filepath.Split(f)
+		for _, p := range commonPlatforms {
+			ctx := build.Default
+			ctx.GOOS, ctx.GOARCH = p.GOOS, p.GOARCH
+			if ok, err := ctx.MatchFile(dir, name); err == nil && ok {
+				result = append(result, f)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// DiffAllPlatforms computes the difference between the public APIs of the
+// projects at the given directories for each of the given platforms, since
+// files with build constraints make the API differ between platforms.
+func DiffAllPlatforms(prevDir, currentDir string, targets []Platform) (map[Platform]APIChanges, error) {
+	var result = make(map[Platform]APIChanges, len(targets))
+	for _, p := range targets {
+		opts := LoadOptions{Env: p.env()}
+		prev, err := ProjectAPIWithOptions(prevDir, opts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get API of %s for %s: %s", prevDir, p, err)
+		}
+
+		current, err := ProjectAPIWithOptions(currentDir, opts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get API of %s for %s: %s", currentDir, p, err)
+		}
+
+		result[p] = Diff(current, prev)
+	}
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package semverlint
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestDiffAllPlatforms(t *testing.T) {
+	prev := testModule(t, map[string]string{
+		"m.go":         packageSource(`func F() {}`),
+		"m_linux.go":   packageSource(`func L() {}`),
+		"m_windows.go": packageSource(`func W() {}`),
+	})
+	current := testModule(t, map[string]string{
+		"m.go":         packageSource(`func F() {}`),
+		"m_linux.go":   packageSource(`func L() {}`),
+		"m_windows.go": packageSource(`func W2() {}`),
+	})
+
+	linux, windows := Platform{"linux", "amd64"}, Platform{"windows", "amd64"}
+	changes, err := DiffAllPlatforms(prev, current, []Platform{linux, windows})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("expected changes for 2 platforms, got %d", len(changes))
+	}
+
+	AssertChanges(t, changes[linux], nil)
+	AssertChanges(t, changes[windows], []string{
+		"example.com/m: function W: was removed",
+		"example.com/m: function W2: was added",
+	})
+}
+
+func TestWarnOtherPlatforms(t *testing.T) {
+	other := "windows"
+	if runtime.GOOS == other {
+		other = "linux"
+	}
+
+	dir := testModule(t, map[string]string{
+		"m.go":                    packageSource(`func F() {}`),
+		"m_" + other + ".go":      packageSource(`func O() {}`),
+		"m_plan9.go":              packageSource(`func P() {}`),
+		"m_" + other + "_test.go": packageSource(`func T() {}`),
+		"gen.go":                  "//go:build ignore\n\npackage main\n\nfunc main() {}\n",
+	})
+
+	var warnings []string
+	api, err := ProjectAPIWithOptions(dir, LoadOptions{
+		Warn: func(msg string) {
+			warnings = append(warnings, msg)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(api) != 1 || len(api[0].Funcs) != 1 {
+		t.Errorf("expected only the API of the host platform, got %v", api)
+	}
+
+	want := "package example.com/m may have more API in other platforms, files excluded by build constraints: m_" + other + ".go"
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("expected warning %q, got %q", want, warnings)
+	}
+}
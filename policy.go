@@ -0,0 +1,110 @@
+package semverlint
+
+// Policy decides which changes of the API are allowed in a release.
+type Policy struct {
+	// StablePackages are the paths of the packages that make up the stable
+	// API of the project. If not empty, breaking changes in any other
+	// package, such as exported packages meant for internal use, don't
+	// require a major version bump and are only reported as warnings.
+	StablePackages []string
+
+	// AllowDeprecatedRemovals allows removing packages that were documented
+	// as deprecated without a major version bump. It requires loading the
+	// APIs with docs.
+	AllowDeprecatedRemovals bool
+}
+
+func (p Policy) isStable(path string) bool {
+	if len(p.StablePackages) == 0 {
+		return true
+	}
+
+	for _, s := range p.StablePackages {
+		if s == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Recommend returns the version bump required by the given changes
+// according to the policy.
+func (p Policy) Recommend(changes APIChanges) Bump {
+	var result = PatchBump
+	for _, pkg := range changes {
+		switch p.severity(pkg) {
+		case Breaking:
+			return MajorBump
+		case Additive:
+			result = MinorBump
+		}
+	}
+	return result
+}
+
+// severity returns the highest severity of the changes of the package
+// allowed by the policy.
+func (p Policy) severity(pkg PackageChanges) Severity {
+	var result = Cosmetic
+	for _, c := range pkg.Changes {
+		if s := p.changeSeverity(pkg, c); s > result {
+			result = s
+		}
+	}
+	return result
+}
+
+// changeSeverity returns the severity of a change of the given package,
+// where the breaking changes allowed by the policy are additive.
+func (p Policy) changeSeverity(pkg PackageChanges, c Change) Severity {
+	s := SeverityOf(c)
+	if s == Breaking && (!p.isStable(pkg.Path) || p.allowedRemoval(c)) {
+		return Additive
+	}
+	return s
+}
+
+// allowedRemoval reports whether the change is the removal of a deprecated
+// package allowed by the policy.
+func (p Policy) allowedRemoval(c Change) bool {
+	d, ok := c.(DeclChange)
+	if !ok || d.Type != PackageType || !p.AllowDeprecatedRemovals {
+		return false
+	}
+
+	for _, c := range d.Changes {
+		if _, ok := c.(WasDeprecated); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns the breaking changes that violate the policy, which should
+// fail the check, and the breaking changes allowed by the policy, which
+// should only be reported as warnings.
+func (p Policy) Check(changes APIChanges) (failures, warnings APIChanges) {
+	for _, pkg := range changes {
+		var failed, allowed []Change
+		for _, c := range pkg.Changes {
+			if !IsBreaking(c) {
+				continue
+			}
+
+			if p.changeSeverity(pkg, c) == Breaking {
+				failed = append(failed, c)
+			} else {
+				allowed = append(allowed, c)
+			}
+		}
+
+		if len(failed) > 0 {
+			failures = append(failures, PackageChanges{Path: pkg.Path, Name: pkg.Name, Changes: failed})
+		}
+
+		if len(allowed) > 0 {
+			warnings = append(warnings, PackageChanges{Path: pkg.Path, Name: pkg.Name, Changes: allowed})
+		}
+	}
+	return failures, warnings
+}
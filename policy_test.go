@@ -0,0 +1,86 @@
+package semverlint
+
+import "testing"
+
+func TestPolicyStablePackages(t *testing.T) {
+	prev := moduleAPI(t, map[string]string{
+		"m.go":       packageSource("func F() {}"),
+		"util/u.go":  "package util\n\nfunc U(a int) {}\n",
+		"extra/e.go": "package extra\n\nfunc E() {}\n",
+	})
+	current := moduleAPI(t, map[string]string{
+		"m.go":       packageSource("func F() {}\n\nfunc G() {}"),
+		"util/u.go":  "package util\n\nfunc U(a string) {}\n",
+		"extra/e.go": "package extra\n\nfunc E() {}\n",
+	})
+	changes := Diff(current, prev)
+
+	if r := Recommend(changes); r != MajorBump {
+		t.Errorf("expected a major bump without a policy, got %s", r)
+	}
+
+	policy := Policy{StablePackages: []string{"example.com/m", "example.com/m/extra"}}
+	if r := policy.Recommend(changes); r != MinorBump {
+		t.Errorf("expected a minor bump when only non-stable packages break, got %s", r)
+	}
+
+	failures, warnings := policy.Check(changes)
+	AssertChanges(t, failures, nil)
+	AssertChanges(t, warnings, []string{
+		`example.com/m/util: function U: argument a with type string at position 0: type changed from "int" to "string"`,
+	})
+
+	policy = Policy{StablePackages: []string{"example.com/m/util"}}
+	if r := policy.Recommend(changes); r != MajorBump {
+		t.Errorf("expected a major bump when a stable package breaks, got %s", r)
+	}
+
+	failures, warnings = policy.Check(changes)
+	AssertChanges(t, failures, []string{
+		`example.com/m/util: function U: argument a with type string at position 0: type changed from "int" to "string"`,
+	})
+	AssertChanges(t, warnings, nil)
+}
+
+func TestPolicyDeprecatedRemovals(t *testing.T) {
+	load := func(files map[string]string) API {
+		api, err := ProjectAPIWithOptions(testModule(t, files), LoadOptions{Docs: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return api
+	}
+
+	prev := load(map[string]string{
+		"m.go":      packageSource("func F() {}"),
+		"old/o.go":  "// Package old does things.\n//\n// Deprecated: use example.com/m instead.\npackage old\n\nfunc O() {}\n",
+		"gone/g.go": "// Package gone does things.\npackage gone\n\nfunc G() {}\n",
+	})
+	withoutOld := load(map[string]string{
+		"m.go":      packageSource("func F() {}"),
+		"gone/g.go": "// Package gone does things.\npackage gone\n\nfunc G() {}\n",
+	})
+	withoutGone := load(map[string]string{
+		"m.go":     packageSource("func F() {}"),
+		"old/o.go": "// Package old does things.\n//\n// Deprecated: use example.com/m instead.\npackage old\n\nfunc O() {}\n",
+	})
+
+	changes := Diff(withoutOld, prev)
+	AssertChanges(t, changes, []string{
+		"example.com/m/old: package old: was removed, was deprecated",
+	})
+
+	if r := Recommend(changes); r != MajorBump {
+		t.Errorf("expected a major bump by default, got %s", r)
+	}
+
+	policy := Policy{AllowDeprecatedRemovals: true}
+	if r := policy.Recommend(changes); r != MinorBump {
+		t.Errorf("expected a minor bump removing a deprecated package, got %s", r)
+	}
+
+	changes = Diff(withoutGone, prev)
+	if r := policy.Recommend(changes); r != MajorBump {
+		t.Errorf("expected a major bump removing a package that was not deprecated, got %s", r)
+	}
+}
@@ -0,0 +1,92 @@
+package semverlint
+
+import "fmt"
+
+// ReceiverChanged is reported when a method of a type changes between a
+// value and a pointer receiver. Methods with pointer receivers can't be
+// called on values that are not addressable, such as map elements, and are
+// not in the method set of values of the type, so they can't be used to
+// implement interfaces. Methods with value receivers, on the other hand,
+// panic when called on a nil pointer. The change also affects whether the
+// method can mutate the value it's called on, since methods with value
+// receivers operate on a copy.
+type ReceiverChanged struct {
+	// Pointer reports whether the method now has a pointer receiver.
+	Pointer bool
+}
+
+func (r ReceiverChanged) String() string {
+	if r.Pointer {
+		return "receiver changed from value to pointer, it can no longer be called on values " +
+			"and may now mutate the value it's called on instead of a copy"
+	}
+	return "receiver changed from pointer to value, calling it on a nil pointer now panics " +
+		"and it operates on a copy, so changes to the receiver are no longer visible to the caller"
+}
+
+// ValueInterfaceLost is reported when values of a type no longer implement
+// an interface they used to because some of its methods now have pointer
+// receivers. Pointers to the type still implement it.
+type ValueInterfaceLost struct {
+	Interface string
+}
+
+func (v ValueInterfaceLost) String() string {
+	return fmt.Sprintf("values no longer implement %s, only pointers do", v.Interface)
+}
+
+// valueInterfacesLost returns the interfaces of the package and the
+// standard interfaces, if enabled in the options, implemented by values of
+// a type with its previous methods but only by pointers with the current
+// ones.
+func valueInterfacesLost(prev, current []Func, prevPkg, currentPkg Package, opts DiffOptions) []Change {
+	prevValue, currentValue := valueMethods(prev), valueMethods(current)
+	currentAll := funcsIndex(current)
+
+	var changes []Change
+	currentIfaces := interfacesIndex(currentPkg.Interfaces)
+	for _, iface := range prevPkg.Interfaces {
+		iface2, ok := currentIfaces[iface.Name]
+		if !ok || len(iface.Methods) == 0 || len(iface.TypeSet) > 0 {
+			continue
+		}
+
+		if implements(prevValue, iface) && !implements(currentValue, iface2) && implements(currentAll, iface2) {
+			changes = append(changes, ValueInterfaceLost{iface.Name})
+		}
+	}
+
+	if opts.StdInterfaces {
+		for _, iface := range stdInterfaces {
+			if iface.implementedBy(prevValue) && !iface.implementedBy(currentValue) && iface.implementedBy(currentAll) {
+				changes = append(changes, ValueInterfaceLost{iface.name})
+			}
+		}
+	}
+
+	return changes
+}
+
+// valueMethods returns the methods in the method set of values of a type
+// indexed by name.
+func valueMethods(methods []Func) map[string]Func {
+	var result = make(map[string]Func)
+	for _, m := range methods {
+		if !m.PointerReceiver {
+			result[m.Name] = m
+		}
+	}
+	return result
+}
+
+// implements reports whether a type with the given methods implements the
+// given interface of the same version of the package.
+func implements(methods map[string]Func, iface Interface) bool {
+	for _, m := range iface.Methods {
+		f, ok := methods[m.Name]
+		if !ok || len(funcDiff(m, f, DiffOptions{})) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package semverlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValueInterfaceLost(t *testing.T) {
+	prev := `
+type Named interface {
+	Name() string
+	SetName(string)
+}
+
+type Closer interface {
+	Close() error
+}
+
+type T struct{ name string }
+
+func (t T) Name() string { return t.name }
+func (t T) SetName(string) {}
+func (t T) Close() error { return nil }
+`
+
+	current := `
+type Named interface {
+	Name() string
+	SetName(string)
+}
+
+type Closer interface {
+	Close() error
+}
+
+type T struct{ name string }
+
+func (t *T) Name() string { return t.name }
+func (t *T) SetName(string) {}
+func (t *T) Close() error { return nil }
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: struct T: method Close: receiver changed from value to pointer, it can no longer be called on values and may now mutate the value it's called on instead of a copy, method Name: receiver changed from value to pointer, it can no longer be called on values and may now mutate the value it's called on instead of a copy, method SetName: receiver changed from value to pointer, it can no longer be called on values and may now mutate the value it's called on instead of a copy`,
+		`example.com/m: struct T: values no longer implement Closer, only pointers do, values no longer implement Named, only pointers do`,
+	})
+
+	AssertChanges(t, diffSourcesWithOptions(t, prev, current, DiffOptions{StdInterfaces: true}), []string{
+		`example.com/m: struct T: method Close: receiver changed from value to pointer, it can no longer be called on values and may now mutate the value it's called on instead of a copy, method Name: receiver changed from value to pointer, it can no longer be called on values and may now mutate the value it's called on instead of a copy, method SetName: receiver changed from value to pointer, it can no longer be called on values and may now mutate the value it's called on instead of a copy`,
+		`example.com/m: struct T: values no longer implement Closer, only pointers do, values no longer implement Named, only pointers do, values no longer implement io.Closer, only pointers do`,
+	})
+
+	// Going back to value receivers only makes calls on nil pointers panic.
+	changes := diffSources(t, current, prev)
+	AssertChanges(t, changes, []string{
+		`example.com/m: struct T: method Close: receiver changed from pointer to value, calling it on a nil pointer now panics and it operates on a copy, so changes to the receiver are no longer visible to the caller, method Name: receiver changed from pointer to value, calling it on a nil pointer now panics and it operates on a copy, so changes to the receiver are no longer visible to the caller, method SetName: receiver changed from pointer to value, calling it on a nil pointer now panics and it operates on a copy, so changes to the receiver are no longer visible to the caller`,
+	})
+
+	if bump := Recommend(changes); bump != PatchBump {
+		t.Errorf("expected a patch bump, got %v", bump)
+	}
+}
+
+func TestValueInterfaceLostPartially(t *testing.T) {
+	// Values still implement an interface if only methods outside of it
+	// move to pointer receivers.
+	changes := diffSources(t, `
+type Namer interface{ Name() string }
+
+type T struct{}
+
+func (T) Name() string { return "" }
+func (T) Reset() {}
+`, `
+type Namer interface{ Name() string }
+
+type T struct{}
+
+func (T) Name() string { return "" }
+func (*T) Reset() {}
+`)
+
+	AssertChanges(t, changes, []string{
+		`example.com/m: struct T: method Reset: receiver changed from value to pointer, it can no longer be called on values and may now mutate the value it's called on instead of a copy`,
+	})
+
+	if bump := Recommend(changes); bump != MajorBump {
+		t.Errorf("expected a major bump, got %v", bump)
+	}
+}
+
+func TestReceiverChangedMutation(t *testing.T) {
+	value := `
+type Counter int
+
+func (c Counter) Inc() Counter { c++; return c }
+`
+	pointer := `
+type Counter int
+
+func (c *Counter) Inc() Counter { *c++; return *c }
+`
+
+	testCases := []struct {
+		name          string
+		prev, current string
+		want          string
+	}{
+		{"value to pointer", value, pointer, "may now mutate the value it's called on instead of a copy"},
+		{"pointer to value", pointer, value, "changes to the receiver are no longer visible to the caller"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := diffSources(t, tc.prev, tc.current).Strings()
+			if len(changes) != 1 || !strings.Contains(changes[0], tc.want) {
+				t.Errorf("expected a single change mentioning %q, got %q", tc.want, changes)
+			}
+		})
+	}
+}
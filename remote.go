@@ -0,0 +1,156 @@
+package semverlint
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+// DiffRemote computes the difference between the public API of the project
+// at the given reference of a remote repository and the public API of the
+// project at the given local directory. The reference is the name of a
+// branch or a tag of the remote, or its default branch if it's empty.
+//
+// Only the commit the reference points to is fetched, and it's kept in
+// memory, so there is no need for a local clone of the repository. HTTP
+// remotes requiring authentication use the credentials in the
+// SEMVERLINT_GIT_USERNAME and SEMVERLINT_GIT_PASSWORD environment variables,
+// and SSH remotes the SSH agent.
+func DiffRemote(remoteURL, ref, localDir string) (APIChanges, error) {
+	auth, err := remoteAuth(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := remoteReference(remoteURL, ref, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           remoteURL,
+		Auth:          auth,
+		ReferenceName: name,
+		SingleBranch:  true,
+		Depth:         1,
+		Tags:          git.NoTags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s from %s: %s", name, remoteURL, err)
+	}
+
+	head, err := r.Head()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get fetched reference: %s", err)
+	}
+
+	hash := head.Hash()
+	// annotated tags point to a tag object instead of a commit
+	if obj, err := r.TagObject(hash); err == nil {
+		hash = obj.Target
+	} else if err != plumbing.ErrObjectNotFound {
+		return nil, fmt.Errorf("unknown error getting tag: %s", err)
+	}
+
+	prev, err := repositoryAPI(r, Version{name.Short(), hash}, LoadOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get API of %s: %s", remoteURL, err)
+	}
+
+	current, err := ProjectAPI(localDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get API of %s: %s", localDir, err)
+	}
+
+	return Diff(current, prev), nil
+}
+
+// remoteReference returns the full name of the given branch or tag of a
+// remote repository, or of its default branch if it's empty.
+func remoteReference(remoteURL, ref string, auth transport.AuthMethod) (plumbing.ReferenceName, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{remoteURL},
+	})
+
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if err != nil {
+		return "", fmt.Errorf("unable to list references of %s: %s", remoteURL, err)
+	}
+
+	if ref == "" {
+		return defaultBranch(remoteURL, refs)
+	}
+
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(ref),
+		plumbing.NewTagReferenceName(ref),
+	} {
+		for _, r := range refs {
+			if r.Name() == name {
+				return name, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("reference %q not found in %s", ref, remoteURL)
+}
+
+// defaultBranch returns the branch HEAD points to in the given references
+// of a remote. Remotes that don't advertise HEAD as a symbolic reference
+// point to the branch with the same commit, preferring master.
+func defaultBranch(remoteURL string, refs []*plumbing.Reference) (plumbing.ReferenceName, error) {
+	var head *plumbing.Reference
+	for _, r := range refs {
+		if r.Name() == plumbing.HEAD {
+			head = r
+		}
+	}
+
+	if head == nil {
+		return "", fmt.Errorf("no HEAD reference found in %s", remoteURL)
+	}
+
+	if head.Type() == plumbing.SymbolicReference {
+		return head.Target(), nil
+	}
+
+	var result plumbing.ReferenceName
+	for _, r := range refs {
+		if r.Name().IsBranch() && r.Hash() == head.Hash() {
+			if result == "" || r.Name() == plumbing.Master {
+				result = r.Name()
+			}
+		}
+	}
+
+	if result == "" {
+		return "", fmt.Errorf("unable to find the default branch of %s", remoteURL)
+	}
+	return result, nil
+}
+
+// remoteAuth returns the credentials for the given remote in the
+// environment, if any. Only HTTP remotes use them.
+func remoteAuth(remoteURL string) (transport.AuthMethod, error) {
+	ep, err := transport.NewEndpoint(remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote %s: %s", remoteURL, err)
+	}
+
+	if ep.Protocol != "http" && ep.Protocol != "https" {
+		return nil, nil
+	}
+
+	user, password := os.Getenv("SEMVERLINT_GIT_USERNAME"), os.Getenv("SEMVERLINT_GIT_PASSWORD")
+	if user == "" && password == "" {
+		return nil, nil
+	}
+	return &http.BasicAuth{Username: user, Password: password}, nil
+}
@@ -0,0 +1,88 @@
+//go:build remote
+
+package semverlint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// The tests of DiffRemote fetch from a remote, so they only run with the
+// remote build tag:
+//
+//	go test -tags remote -run DiffRemote .
+
+func TestDiffRemote(t *testing.T) {
+	r := newTestRepo(t)
+	r.tag("v1.0.0", r.commit(map[string]string{
+		"m.go": packageSource(`func F() {}`),
+	}))
+	r.branch("main", r.commit(map[string]string{
+		"m.go": packageSource(`func F() {}
+
+func G() {}`),
+	}))
+
+	// A bare repository with the branches and tags of the fixture and main
+	// as its default branch acts as the remote.
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	bare, err := git.PlainInit(remote, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origin, err := r.repo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{remote},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = origin.Push(&git.PushOptions{RefSpecs: []config.RefSpec{
+		"refs/heads/*:refs/heads/*",
+		"refs/tags/*:refs/tags/*",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head := plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))
+	if err := bare.Storer.SetReference(head); err != nil {
+		t.Fatal(err)
+	}
+
+	local := testModule(t, map[string]string{
+		"m.go": packageSource(`func G() {}`),
+	})
+
+	testCases := []struct {
+		ref  string
+		want []string
+	}{
+		{"", []string{`example.com/m: function F: was removed`}},
+		{"main", []string{`example.com/m: function F: was removed`}},
+		{"v1.0.0", []string{
+			`example.com/m: function F: was removed`,
+			`example.com/m: function G: was added`,
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ref, func(t *testing.T) {
+			changes, err := DiffRemote("file://"+remote, tc.ref, local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			AssertChanges(t, changes, tc.want)
+		})
+	}
+
+	if _, err := DiffRemote("file://"+remote, "missing", local); err == nil {
+		t.Error("expected an error for a missing reference")
+	}
+}
@@ -0,0 +1,115 @@
+package semverlint
+
+// renames merges the removal and addition of declarations of the same kind
+// and shape whose names are at most opts.RenameThreshold edits apart into a
+// single Renamed change.
+func renames(prev, current Package, changes []Change, opts DiffOptions) []Change {
+	threshold := opts.RenameThreshold
+	var added []DeclChange
+	for _, c := range changes {
+		if d, ok := c.(DeclChange); ok && isDeclChange(d, Added{}) {
+			added = append(added, d)
+		}
+	}
+
+	var renamedTo = make(map[string]struct{})
+	var result = make([]Change, 0, len(changes))
+	for _, c := range changes {
+		d, ok := c.(DeclChange)
+		if !ok || !isDeclChange(d, Removed{}) {
+			result = append(result, c)
+			continue
+		}
+
+		var best string
+		var bestDistance = threshold + 1
+		for _, a := range added {
+			if _, ok := renamedTo[a.Name]; ok || a.Type != d.Type {
+				continue
+			}
+
+			dist := editDistance(d.Name, a.Name)
+			if dist < bestDistance && sameShape(prev, current, d.Type, d.Name, a.Name, opts) {
+				best, bestDistance = a.Name, dist
+			}
+		}
+
+		if best == "" {
+			result = append(result, c)
+			continue
+		}
+
+		renamedTo[best] = struct{}{}
+		result = append(result, NewDeclChange(d.Name, d.Type, Renamed{
+			From: d.Name,
+			To:   best,
+		}))
+	}
+
+	var filtered = result[:0]
+	for _, c := range result {
+		if d, ok := c.(DeclChange); ok && isDeclChange(d, Added{}) {
+			if _, ok := renamedTo[d.Name]; ok {
+				continue
+			}
+		}
+		filtered = append(filtered, c)
+	}
+
+	return filtered
+}
+
+// sameShape reports whether the declaration of the given kind named a in
+// the previous package has the same shape as the one named b in the current
+// package, ignoring cosmetic differences.
+func sameShape(prev, current Package, typ DeclType, a, b string, opts DiffOptions) bool {
+	opts.ReportCosmetic = false
+	switch typ {
+	case ConstType:
+		c1, c2 := constsIndex(prev.Consts)[a], constsIndex(current.Consts)[b]
+		return typesEqual(c1.Type, c2.Type, opts) && c1.Value == c2.Value
+	case VarType:
+		return typesEqual(varsIndex(prev.Vars)[a].Type, varsIndex(current.Vars)[b].Type, opts)
+	case FuncType:
+		return len(funcDiff(funcsIndex(prev.Funcs)[a], funcsIndex(current.Funcs)[b], opts)) == 0
+	case StructType:
+		s1, s2 := structsIndex(prev.Structs)[a], structsIndex(current.Structs)[b]
+		return len(fieldsDiff(s1.Fields, s2.Fields, opts)) == 0 &&
+			len(methodsDiff(s1.Methods, s2.Methods, opts, false)) == 0
+	case InterfaceType:
+		i1, i2 := interfacesIndex(prev.Interfaces)[a], interfacesIndex(current.Interfaces)[b]
+		return typeSetsEqual(i1.TypeSet, i2.TypeSet, opts) &&
+			len(methodsDiff(i1.Methods, i2.Methods, opts, true)) == 0
+	case TypeDefType:
+		t1, t2 := typesIndex(prev.Types)[a], typesIndex(current.Types)[b]
+		return t1.Alias == t2.Alias && typesEqual(t1.Type, t2.Type, opts) &&
+			len(methodsDiff(t1.Methods, t2.Methods, opts, false)) == 0
+	default:
+		return false
+	}
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cur := row[j]
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			row[j] = min(row[j]+1, row[j-1]+1, prev+cost)
+			prev = cur
+		}
+	}
+
+	return row[len(rb)]
+}
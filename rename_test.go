@@ -0,0 +1,25 @@
+package semverlint
+
+import "testing"
+
+func TestRenames(t *testing.T) {
+	prev := `type Confg struct{ Name string }`
+	current := `type Config struct{ Name string }`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		"example.com/m: struct Confg: was removed",
+		"example.com/m: struct Config: was added",
+	})
+
+	opts := DiffOptions{RenameThreshold: 1}
+	AssertChanges(t, diffSourcesWithOptions(t, prev, current, opts), []string{
+		"example.com/m: struct Confg: was probably renamed from Confg to Config",
+	})
+
+	// Structs with different fields are not the same declaration.
+	current = `type Config struct{ Name, Value string }`
+	AssertChanges(t, diffSourcesWithOptions(t, prev, current, opts), []string{
+		"example.com/m: struct Confg: was removed",
+		"example.com/m: struct Config: was added",
+	})
+}
@@ -0,0 +1,206 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// UnifiedText renders the changes as a textual diff of the signatures of the
+// changed declarations, similar to the output of git diff, with the old
+// signature prefixed by "-" and the new one prefixed by "+".
+func (c APIChanges) UnifiedText() string {
+	var b strings.Builder
+	for _, pkg := range c {
+		var seen = make(map[string]struct{})
+		var header bool
+		for _, change := range pkg.Changes {
+			d, ok := change.(DeclChange)
+			if !ok || d.Before == d.After {
+				continue
+			}
+
+			key := d.Before + "\x00" + d.After
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			if !header {
+				fmt.Fprintf(&b, "--- %s\n+++ %s\n", pkg.Path, pkg.Path)
+				header = true
+			}
+
+			writePrefixed(&b, "-", d.Before)
+			writePrefixed(&b, "+", d.After)
+		}
+	}
+	return b.String()
+}
+
+func writePrefixed(b *strings.Builder, prefix, text string) {
+	if text == "" {
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(b, "%s %s\n", prefix, line)
+	}
+}
+
+// withSignatures sets the signatures before and after the change of the
+// declarations changed between the two packages.
+func withSignatures(prev, current Package, changes []Change) []Change {
+	for i, c := range changes {
+		d, ok := c.(DeclChange)
+		if !ok {
+			continue
+		}
+
+		typ, name := d.Type, d.Name
+		for _, c := range d.Changes {
+			switch c := c.(type) {
+			case KindChanged:
+				typ = c.To
+			case Renamed:
+				name = c.To
+			}
+		}
+
+		d.Before = declSignature(prev, d.Type, d.Name)
+		d.After = declSignature(current, typ, name)
+		changes[i] = d
+	}
+	return changes
+}
+
+// declSignature renders the declaration of the given type and name in the
+// package, or returns an empty string if there is no such declaration.
+func declSignature(pkg Package, typ DeclType, name string) string {
+	switch typ {
+	case ConstType:
+		if c, ok := constsIndex(pkg.Consts)[name]; ok {
+			return fmt.Sprintf("const %s %s = %s", c.Name, shortTypeString(c.Type), c.Value)
+		}
+	case VarType:
+		if v, ok := varsIndex(pkg.Vars)[name]; ok {
+			return fmt.Sprintf("var %s %s", v.Name, shortTypeString(v.Type))
+		}
+	case FuncType:
+		if f, ok := funcsIndex(pkg.Funcs)[name]; ok {
+			return renderSignature(f)
+		}
+	case StructType:
+		if s, ok := structsIndex(pkg.Structs)[name]; ok {
+			var b strings.Builder
+			fmt.Fprintf(&b, "type %s%s struct {\n", s.Name, typeParamsString(s.TypeParams))
+			for _, f := range s.Fields {
+				if ast.IsExported(f.Name) {
+					fmt.Fprintf(&b, "\t%s %s\n", f.Name, shortTypeString(f.Type))
+				}
+			}
+			b.WriteString("}")
+			for _, m := range s.Methods {
+				fmt.Fprintf(&b, "\nfunc (%s) %s%s", receiverString(s.Name, m), m.Name, signatureString(m))
+			}
+			return b.String()
+		}
+	case InterfaceType:
+		if iface, ok := interfacesIndex(pkg.Interfaces)[name]; ok {
+			var b strings.Builder
+			fmt.Fprintf(&b, "type %s%s interface {\n", iface.Name, typeParamsString(iface.TypeParams))
+			for _, t := range iface.TypeSet {
+				fmt.Fprintf(&b, "\t%s\n", shortTypeString(t))
+			}
+			for _, m := range iface.Methods {
+				fmt.Fprintf(&b, "\t%s%s\n", m.Name, signatureString(m))
+			}
+			b.WriteString("}")
+			return b.String()
+		}
+	case TypeDefType:
+		if t, ok := typesIndex(pkg.Types)[name]; ok {
+			if t.Alias {
+				return fmt.Sprintf("type %s%s = %s", t.Name, typeParamsString(t.TypeParams), shortTypeString(t.Type))
+			}
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "type %s%s %s", t.Name, typeParamsString(t.TypeParams), shortTypeString(t.Type))
+			for _, m := range t.Methods {
+				fmt.Fprintf(&b, "\nfunc (%s) %s%s", receiverString(t.Name, m), m.Name, signatureString(m))
+			}
+			return b.String()
+		}
+	}
+	return ""
+}
+
+// renderSignature renders the declaration of a function with the packages
+// of its types qualified by their names, e.g.
+// func Get(ctx context.Context, keys ...string) (Result, error).
+func renderSignature(f Func) string {
+	var name = "func"
+	if f.Name != "" {
+		name += " " + f.Name
+	}
+	return name + typeParamsString(f.TypeParams) + signatureString(f)
+}
+
+// receiverString renders the receiver of a method of the type with the
+// given name, which is a pointer for the methods with pointer receivers.
+func receiverString(typ string, m Func) string {
+	if m.PointerReceiver {
+		return "*" + typ
+	}
+	return typ
+}
+
+// signatureString renders the parameters and results of a function.
+func signatureString(f Func) string {
+	s := "(" + paramsString(f.Args, f.Variadic) + ")"
+	switch {
+	case len(f.Return) == 1 && f.Return[0].Name == "":
+		s += " " + shortTypeString(f.Return[0].Type)
+	case len(f.Return) > 0:
+		s += " (" + paramsString(f.Return, false) + ")"
+	}
+	return s
+}
+
+func paramsString(params []Param, variadic bool) string {
+	var strs = make([]string, len(params))
+	for i, p := range params {
+		strs[i] = shortTypeString(p.Type)
+		if s, ok := p.Type.(*types.Slice); ok && variadic && i == len(params)-1 {
+			strs[i] = "..." + shortTypeString(s.Elem())
+		}
+
+		if p.Name != "" {
+			strs[i] = p.Name + " " + strs[i]
+		}
+	}
+	return strings.Join(strs, ", ")
+}
+
+// shortTypeString renders a type with the packages it refers to qualified
+// by their names instead of their import paths, e.g. context.Context
+// instead of the verbose types.Type.String. Types read back from JSON are
+// rendered as they were written.
+func shortTypeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		return p.Name()
+	})
+}
+
+func typeParamsString(tparams []TypeParam) string {
+	if len(tparams) == 0 {
+		return ""
+	}
+
+	var strs = make([]string, len(tparams))
+	for i, tp := range tparams {
+		strs[i] = tp.Name + " " + shortTypeString(tp.Constraint)
+	}
+	return "[" + strings.Join(strs, ", ") + "]"
+}
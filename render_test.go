@@ -0,0 +1,89 @@
+package semverlint
+
+import "testing"
+
+func TestUnifiedText(t *testing.T) {
+	prev := `
+import "io"
+
+func Open(name string) (io.Reader, error) { return nil, nil }
+
+func Close() {}
+
+type Options struct {
+	Name string
+	Size int
+}
+
+func (Options) Validate() error { return nil }
+
+type Reader interface {
+	Read(p []byte) (int, error)
+}
+`
+	current := `
+import "io"
+
+func Open(name string, flags int) (io.ReadCloser, error) { return nil, nil }
+
+func Flush() error { return nil }
+
+type Options struct {
+	Name string
+	Size int64
+}
+
+func (*Options) Validate() error { return nil }
+
+type Reader interface {
+	Read(p []byte) (int, error)
+	Close() error
+}
+`
+
+	assertGolden(t, "unified.golden", diffSources(t, prev, current).UnifiedText())
+}
+
+func TestRenderSignature(t *testing.T) {
+	api := sourceAPI(t, `
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+type Result struct{}
+
+func Get(ctx context.Context, keys ...string) (Result, error) { return Result{}, nil }
+
+func Copy(dst io.Writer, src io.Reader) (written int64, err error) { return 0, nil }
+
+func Handle(h http.Handler, opts ...func(*http.Server)) {}
+
+func Map[K comparable, V any](m map[K]V, fn func(K, V) bool) []V { return nil }
+
+func Noop() {}
+
+func Unnamed(int, ...[]byte) error { return nil }
+`)
+
+	want := map[string]string{
+		"Get":     "func Get(ctx context.Context, keys ...string) (m.Result, error)",
+		"Copy":    "func Copy(dst io.Writer, src io.Reader) (written int64, err error)",
+		"Handle":  "func Handle(h http.Handler, opts ...func(*http.Server))",
+		"Map":     "func Map[K comparable, V any](m map[K]V, fn func(K, V) bool) []V",
+		"Noop":    "func Noop()",
+		"Unnamed": "func Unnamed(int, ...[]byte) error",
+	}
+
+	funcs := api[0].Funcs
+	if len(funcs) != len(want) {
+		t.Fatalf("expected %d functions, got %d", len(want), len(funcs))
+	}
+
+	for _, f := range funcs {
+		if got := renderSignature(f); got != want[f.Name] {
+			t.Errorf("%s: expected %q, got %q", f.Name, want[f.Name], got)
+		}
+	}
+}
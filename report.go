@@ -0,0 +1,42 @@
+package semverlint
+
+// PackageReport is a serializable report of the changes of a package.
+type PackageReport struct {
+	Path    string         `json:"path"`
+	Summary Summary        `json:"summary"`
+	Changes []ChangeReport `json:"changes"`
+}
+
+// ChangeReport is a serializable report of a top-level change.
+type ChangeReport struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Change   string `json:"change"`
+}
+
+// Report returns a serializable report of the changes, e.g. to encode them
+// as JSON, with a report for each package in the same order.
+func (c APIChanges) Report() []PackageReport {
+	var result = make([]PackageReport, len(c))
+	for i, pkg := range c {
+		result[i] = PackageReport{
+			Path:    pkg.Path,
+			Summary: APIChanges{pkg}.Summarize(),
+			Changes: make([]ChangeReport, len(pkg.Changes)),
+		}
+
+		for j, change := range pkg.Changes {
+			var decl string
+			if d, ok := change.(DeclChange); ok {
+				decl = d.Name
+			}
+
+			result[i].Changes[j] = ChangeReport{
+				ID:       ID(pkg.Path, decl, change),
+				Severity: SeverityOf(change).String(),
+				Change:   change.String(),
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package semverlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Reporter writes a report of the changes in some format.
+type Reporter interface {
+	Report(w io.Writer, changes APIChanges) error
+}
+
+// ReporterFunc is a function used as a Reporter.
+type ReporterFunc func(w io.Writer, changes APIChanges) error
+
+// Report calls f.
+func (f ReporterFunc) Report(w io.Writer, changes APIChanges) error {
+	return f(w, changes)
+}
+
+var (
+	reportersMu sync.RWMutex
+	reporters   = map[string]Reporter{
+		"text": ReporterFunc(reportText),
+		"diff": ReporterFunc(reportDiff),
+		"json": ReporterFunc(reportJSON),
+	}
+)
+
+// RegisterReporter makes a reporter available with the given name, which
+// replaces the reporter previously registered with the same name, if any.
+func RegisterReporter(name string, r Reporter) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	reporters[name] = r
+}
+
+// LookupReporter returns the reporter registered with the given name.
+func LookupReporter(name string) (Reporter, bool) {
+	reportersMu.RLock()
+	defer reportersMu.RUnlock()
+	r, ok := reporters[name]
+	return r, ok
+}
+
+// Reporters returns the sorted names of the registered reporters.
+func Reporters() []string {
+	reportersMu.RLock()
+	defer reportersMu.RUnlock()
+	var names = make([]string, 0, len(reporters))
+	for name := range reporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// reportText writes a line for each change as returned by Strings.
+func reportText(w io.Writer, changes APIChanges) error {
+	for _, s := range changes.Strings() {
+		if _, err := fmt.Fprintln(w, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportDiff writes the changes as returned by UnifiedText.
+func reportDiff(w io.Writer, changes APIChanges) error {
+	_, err := io.WriteString(w, changes.UnifiedText())
+	return err
+}
+
+// reportJSON writes the changes as returned by Report encoded as JSON.
+func reportJSON(w io.Writer, changes APIChanges) error {
+	return json.NewEncoder(w).Encode(changes.Report())
+}
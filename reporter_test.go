@@ -0,0 +1,71 @@
+package semverlint
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestReporters(t *testing.T) {
+	changes := diffSources(t, `
+func F(a int) {}
+func G() {}
+`, `
+func F(a string) {}
+func H() {}
+`)
+
+	for _, name := range Reporters() {
+		t.Run(name, func(t *testing.T) {
+			r, ok := LookupReporter(name)
+			if !ok {
+				t.Fatalf("reporter %s not found", name)
+			}
+
+			var b bytes.Buffer
+			if err := r.Report(&b, changes); err != nil {
+				t.Fatal(err)
+			}
+
+			if b.Len() == 0 {
+				t.Errorf("expected a non-empty report")
+			}
+		})
+	}
+}
+
+func TestRegisterReporter(t *testing.T) {
+	if _, ok := LookupReporter("count"); ok {
+		t.Fatal("expected count reporter not to be registered")
+	}
+
+	RegisterReporter("count", ReporterFunc(func(w io.Writer, changes APIChanges) error {
+		_, err := io.WriteString(w, strconv.Itoa(len(changes.Strings())))
+		return err
+	}))
+	defer func() {
+		reportersMu.Lock()
+		delete(reporters, "count")
+		reportersMu.Unlock()
+	}()
+
+	if want := []string{"count", "diff", "json", "text"}; !reflect.DeepEqual(Reporters(), want) {
+		t.Errorf("expected reporters %v, got %v", want, Reporters())
+	}
+
+	r, ok := LookupReporter("count")
+	if !ok {
+		t.Fatal("expected count reporter to be registered")
+	}
+
+	var b bytes.Buffer
+	if err := r.Report(&b, diffSources(t, "func F() {}", "func G() {}")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := b.String(); got != "2" {
+		t.Errorf("expected report %q, got %q", "2", got)
+	}
+}
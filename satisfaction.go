@@ -0,0 +1,106 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+)
+
+// SatisfactionMatrix returns the exported interfaces of the API implemented
+// by each of its exported types that are not interfaces, both keyed by
+// their package path and name, e.g. github.com/me/mod/pkg.Foo. Pointers
+// to the types are keyed with a leading "*", since they may implement more
+// interfaces than the values. Interfaces without methods and constraint
+// interfaces are not taken into account, and types implementing none of
+// them are omitted.
+func SatisfactionMatrix(api API) map[string][]string {
+	var ifaces []Interface
+	var ifaceNames []string
+	for _, pkg := range api {
+		for _, iface := range pkg.Interfaces {
+			if ast.IsExported(iface.Name) && len(iface.Methods) > 0 && len(iface.TypeSet) == 0 {
+				ifaces = append(ifaces, iface)
+				ifaceNames = append(ifaceNames, pkg.Path+"."+iface.Name)
+			}
+		}
+	}
+
+	var result = make(map[string][]string)
+	add := func(key string, methods map[string]Func) {
+		for i, iface := range ifaces {
+			if implements(methods, iface) {
+				result[key] = append(result[key], ifaceNames[i])
+			}
+		}
+	}
+
+	for _, pkg := range api {
+		var types = make(map[string][]Func)
+		for _, s := range pkg.Structs {
+			types[s.Name] = s.Methods
+		}
+
+		for _, t := range pkg.Types {
+			if !t.Alias {
+				types[t.Name] = t.Methods
+			}
+		}
+
+		for name, methods := range types {
+			if ast.IsExported(name) {
+				add(pkg.Path+"."+name, valueMethods(methods))
+				add("*"+pkg.Path+"."+name, funcsIndex(methods))
+			}
+		}
+	}
+
+	for _, names := range result {
+		sort.Strings(names)
+	}
+	return result
+}
+
+// SatisfactionChange is a cell of the satisfaction matrix that changed
+// between two versions of an API.
+type SatisfactionChange struct {
+	Type      string
+	Interface string
+	// Implements reports whether the type implements the interface in the
+	// current version, and didn't in the previous one.
+	Implements bool
+}
+
+func (s SatisfactionChange) String() string {
+	if s.Implements {
+		return fmt.Sprintf("%s now implements %s", s.Type, s.Interface)
+	}
+	return fmt.Sprintf("%s no longer implements %s", s.Type, s.Interface)
+}
+
+// DiffSatisfaction returns the cells of the satisfaction matrices, as
+// returned by SatisfactionMatrix, that changed between two versions of an
+// API, sorted by type and interface. Types and interfaces that were removed
+// or added are reported as well.
+func DiffSatisfaction(current, prev map[string][]string) []SatisfactionChange {
+	var changes []SatisfactionChange
+	diff := func(from, to map[string][]string, implements bool) {
+		for typ, ifaces := range to {
+			for _, iface := range ifaces {
+				if !containsString(from[typ], iface) {
+					changes = append(changes, SatisfactionChange{typ, iface, implements})
+				}
+			}
+		}
+	}
+
+	diff(prev, current, true)
+	diff(current, prev, false)
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Type != changes[j].Type {
+			return changes[i].Type < changes[j].Type
+		}
+		return changes[i].Interface < changes[j].Interface
+	})
+	return changes
+}
@@ -0,0 +1,95 @@
+package semverlint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSatisfactionMatrix(t *testing.T) {
+	api := moduleAPI(t, map[string]string{
+		"m.go": packageSource(`
+type Reader interface{ Read() ([]byte, error) }
+
+type Writer interface{ Write([]byte) error }
+
+type Any interface{}
+
+type File struct{}
+
+func (File) Read() ([]byte, error) { return nil, nil }
+func (*File) Write([]byte) error  { return nil }
+
+type Buffer []byte
+
+func (Buffer) Read() ([]byte, error) { return nil, nil }
+
+type None struct{}
+
+type unexported struct{}
+
+func (unexported) Read() ([]byte, error) { return nil, nil }
+`),
+		"w/w.go": "package w\n\ntype W struct{}\n\nfunc (W) Write([]byte) error { return nil }\n",
+	})
+
+	want := map[string][]string{
+		"example.com/m.File":    {"example.com/m.Reader"},
+		"*example.com/m.File":   {"example.com/m.Reader", "example.com/m.Writer"},
+		"example.com/m.Buffer":  {"example.com/m.Reader"},
+		"*example.com/m.Buffer": {"example.com/m.Reader"},
+		"example.com/m/w.W":     {"example.com/m.Writer"},
+		"*example.com/m/w.W":    {"example.com/m.Writer"},
+	}
+
+	if got := SatisfactionMatrix(api); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected matrix %v, got %v", want, got)
+	}
+}
+
+func TestDiffSatisfaction(t *testing.T) {
+	ifaces := `
+type Reader interface{ Read() ([]byte, error) }
+
+type Closer interface{ Close() error }
+`
+	prev := moduleAPI(t, map[string]string{"m.go": packageSource(ifaces + `
+type File struct{}
+
+func (File) Read() ([]byte, error) { return nil, nil }
+
+type Gone struct{}
+
+func (Gone) Close() error { return nil }
+`)})
+
+	current := moduleAPI(t, map[string]string{"m.go": packageSource(ifaces + `
+type File struct{}
+
+func (File) Read(n int) ([]byte, error) { return nil, nil }
+func (File) Close() error { return nil }
+`)})
+
+	got := DiffSatisfaction(SatisfactionMatrix(current), SatisfactionMatrix(prev))
+
+	var strs []string
+	for _, c := range got {
+		strs = append(strs, c.String())
+	}
+
+	want := []string{
+		"*example.com/m.File now implements example.com/m.Closer",
+		"*example.com/m.File no longer implements example.com/m.Reader",
+		"*example.com/m.Gone no longer implements example.com/m.Closer",
+		"example.com/m.File now implements example.com/m.Closer",
+		"example.com/m.File no longer implements example.com/m.Reader",
+		"example.com/m.Gone no longer implements example.com/m.Closer",
+	}
+
+	if !reflect.DeepEqual(strs, want) {
+		t.Errorf("expected changes %q, got %q", want, strs)
+	}
+
+	if changes := DiffSatisfaction(SatisfactionMatrix(current), SatisfactionMatrix(current)); len(changes) != 0 {
+		t.Errorf("expected no changes for the same API, got %v", changes)
+	}
+}
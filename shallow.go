@@ -0,0 +1,105 @@
+package semverlint
+
+import (
+	"go/ast"
+	"sort"
+)
+
+// DiffShallow computes the difference between the exported names of two
+// given public APIs, ignoring any change in the declarations themselves,
+// such as their types or signatures. Only Added and Removed changes are
+// reported, for packages and the declarations in them. It's much cheaper
+// than Diff, so it can be used as a quick check before a full diff.
+func DiffShallow(current, prev API) APIChanges {
+	var changes APIChanges
+	currentPkgs := packagesIndex(current)
+
+	var seen = make(map[string]struct{})
+	for _, p1 := range prev {
+		seen[p1.Path] = struct{}{}
+		p2, ok := currentPkgs[p1.Path]
+		if !ok {
+			changes = append(changes, NewPackageChanges(
+				p1.Name, p1.Path,
+				NewDeclChange(p1.Name, PackageType, Removed{}),
+			))
+			continue
+		}
+
+		changes = append(changes, NewPackageChanges(
+			p2.Name, p2.Path,
+			namesDiff(declNames(p1), declNames(p2))...,
+		))
+	}
+
+	for _, p := range current {
+		if _, ok := seen[p.Path]; !ok {
+			changes = append(changes, NewPackageChanges(
+				p.Name, p.Path,
+				NewDeclChange(p.Name, PackageType, Added{}),
+			))
+		}
+	}
+
+	return changes
+}
+
+// namesDiff returns the declarations removed and added between two sets of
+// names, sorted by name. A declaration that changed its kind keeps its name,
+// so it's not reported.
+func namesDiff(prev, current map[string]DeclType) []Change {
+	var names = make([]string, 0, len(prev)+len(current))
+	for name := range prev {
+		names = append(names, name)
+	}
+	for name := range current {
+		if _, ok := prev[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	for _, name := range names {
+		typ, inPrev := prev[name]
+		typ2, inCurrent := current[name]
+		switch {
+		case !inCurrent:
+			changes = append(changes, NewDeclChange(name, typ, Removed{}))
+		case !inPrev:
+			changes = append(changes, NewDeclChange(name, typ2, Added{}))
+		}
+	}
+	return changes
+}
+
+// declNames returns the kinds of the exported declarations of a package by
+// name.
+func declNames(p Package) map[string]DeclType {
+	var names = make(map[string]DeclType)
+	add := func(name string, typ DeclType) {
+		if ast.IsExported(name) {
+			names[name] = typ
+		}
+	}
+
+	for _, v := range p.Vars {
+		add(v.Name, VarType)
+	}
+	for _, c := range p.Consts {
+		add(c.Name, ConstType)
+	}
+	for _, f := range p.Funcs {
+		add(f.Name, FuncType)
+	}
+	for _, s := range p.Structs {
+		add(s.Name, StructType)
+	}
+	for _, i := range p.Interfaces {
+		add(i.Name, InterfaceType)
+	}
+	for _, t := range p.Types {
+		add(t.Name, TypeDefType)
+	}
+	return names
+}
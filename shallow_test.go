@@ -0,0 +1,63 @@
+package semverlint
+
+import "testing"
+
+func TestDiffShallow(t *testing.T) {
+	prev := moduleAPI(t, map[string]string{
+		"m.go": packageSource(`
+type T struct{ X int }
+
+type I interface{ M() }
+
+type ID int
+
+const C = 1
+
+var V int
+
+func F(int) {}
+
+func Removed() {}
+
+type Kind struct{}
+`),
+		"a/a.go": "package a\n\nfunc A() {}\n",
+		"b/b.go": "package b\n\nfunc B() {}\n",
+	})
+
+	current := moduleAPI(t, map[string]string{
+		"m.go": packageSource(`
+type T struct{ X string }
+
+type I interface{ M(int) error }
+
+type ID string
+
+const C = "1"
+
+var V string
+
+func F(string) error { return nil }
+
+func Added() {}
+
+type Kind interface{}
+
+func unexported() {}
+`),
+		"a/a.go": "package a\n\nfunc A(int) {}\n",
+		"c/c.go": "package c\n\nfunc C() {}\n",
+	})
+
+	AssertChanges(t, DiffShallow(current, prev), []string{
+		`example.com/m: function Added: was added`,
+		`example.com/m: function Removed: was removed`,
+		`example.com/m/b: package b: was removed`,
+		`example.com/m/c: package c: was added`,
+	})
+}
+
+func TestDiffShallowNoChanges(t *testing.T) {
+	api := moduleAPI(t, map[string]string{"m.go": packageSource(`func F() {}`)})
+	AssertChanges(t, DiffShallow(api, api), nil)
+}
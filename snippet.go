@@ -0,0 +1,143 @@
+package semverlint
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Snippets is a reporter that writes each change followed by the source of
+// the changed declaration before and after the change. The APIs must have
+// been loaded from the given directories with the Files option, so the
+// files of the declarations can be found.
+type Snippets struct {
+	PrevDir    string
+	CurrentDir string
+	Prev       API
+	Current    API
+	// Context is the number of lines around the declaration to include.
+	Context int
+}
+
+// Report writes the changes with their snippets.
+func (s Snippets) Report(w io.Writer, changes APIChanges) error {
+	prevPkgs, currentPkgs := packagesIndex(s.Prev), packagesIndex(s.Current)
+	for _, pkg := range changes {
+		for _, c := range pkg.Changes {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", pkg.Path, c); err != nil {
+				return err
+			}
+
+			d, ok := c.(DeclChange)
+			if !ok || d.Type == PackageType {
+				continue
+			}
+
+			sides := []struct {
+				name string
+				dir  string
+				pkg  Package
+			}{
+				{"before", s.PrevDir, prevPkgs[pkg.Path]},
+				{"after", s.CurrentDir, currentPkgs[pkg.Path]},
+			}
+			for _, side := range sides {
+				snippet, err := declSnippet(side.dir, side.pkg.Files, d.Name, s.Context)
+				if err != nil {
+					return err
+				}
+
+				if snippet == "" {
+					continue
+				}
+
+				if _, err := fmt.Fprintf(w, "  %s %s", side.name, snippet); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// declSnippet returns the source of the top-level declaration with the
+// given name, along with its location, found in the given files relative
+// to dir. It's empty if the declaration is not found.
+func declSnippet(dir string, files []string, name string, context int) (string, error) {
+	fset := token.NewFileSet()
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to read file %s: %s", f, err)
+		}
+
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse file %s: %s", f, err)
+		}
+
+		decl := findDecl(file, name)
+		if decl == nil {
+			continue
+		}
+
+		start := fset.Position(decl.Pos()).Line
+		end := fset.Position(decl.End()).Line
+		return fmt.Sprintf("(%s:%d):\n%s", f, start, sourceLines(src, start-context, end+context)), nil
+	}
+	return "", nil
+}
+
+// findDecl returns the top-level declaration of the given name that is not
+// a method.
+func findDecl(file *ast.File, name string) ast.Node {
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && d.Name.Name == name {
+				return d
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.Name == name {
+						return s
+					}
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if n.Name == name {
+							return s
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sourceLines returns the lines of the source between the given ones, both
+// included and starting at 1, prefixed by their numbers.
+func sourceLines(src []byte, from, to int) string {
+	lines := bytes.Split(bytes.TrimSuffix(src, []byte("\n")), []byte("\n"))
+	if from < 1 {
+		from = 1
+	}
+	if to > len(lines) {
+		to = len(lines)
+	}
+
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		fmt.Fprintf(&b, "    %4d | %s\n", i, lines[i-1])
+	}
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package semverlint
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSnippets(t *testing.T) {
+	load := func(decls string) (string, API) {
+		dir := testModule(t, map[string]string{"m.go": packageSource(decls)})
+		api, err := ProjectAPIWithOptions(dir, LoadOptions{Files: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dir, api
+	}
+
+	prevDir, prev := load(`
+// Open opens the file.
+func Open(name string) error { return nil }
+
+func Close() {}
+`)
+	currentDir, current := load(`
+func Close() {}
+
+// Open opens the file with the given flags.
+func Open(name string, flags int) error {
+	return nil
+}
+`)
+
+	var b bytes.Buffer
+	s := Snippets{
+		PrevDir:    prevDir,
+		CurrentDir: currentDir,
+		Prev:       prev,
+		Current:    current,
+		Context:    1,
+	}
+	if err := s.Report(&b, Diff(current, prev)); err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "snippets.golden", b.String())
+}
@@ -0,0 +1,100 @@
+package semverlint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InterfaceLost is reported when a type no longer implements a well-known
+// interface of the standard library, so it can't be used anymore where that
+// interface is expected.
+type InterfaceLost struct {
+	Interface string
+	// Missing are the methods of the interface the type no longer has
+	// with the right signature.
+	Missing []string
+}
+
+func (i InterfaceLost) String() string {
+	if len(i.Missing) == 0 {
+		return fmt.Sprintf("no longer implements %s", i.Interface)
+	}
+	return fmt.Sprintf("no longer implements %s, missing %s", i.Interface, strings.Join(i.Missing, ", "))
+}
+
+type stdInterface struct {
+	name    string
+	methods []stdMethod
+}
+
+// stdMethod is the signature of a method of a standard interface, with the
+// types of its arguments and results as written by typeString.
+type stdMethod struct {
+	name    string
+	args    []string
+	results []string
+}
+
+var stdInterfaces = []stdInterface{
+	{"error", []stdMethod{{"Error", nil, []string{"string"}}}},
+	{"fmt.Stringer", []stdMethod{{"String", nil, []string{"string"}}}},
+	{"io.Reader", []stdMethod{{"Read", []string{"[]byte"}, []string{"int", "error"}}}},
+	{"io.Writer", []stdMethod{{"Write", []string{"[]byte"}, []string{"int", "error"}}}},
+	{"io.Closer", []stdMethod{{"Close", nil, []string{"error"}}}},
+	{"io.ReaderFrom", []stdMethod{{"ReadFrom", []string{"io.Reader"}, []string{"int64", "error"}}}},
+	{"io.WriterTo", []stdMethod{{"WriteTo", []string{"io.Writer"}, []string{"int64", "error"}}}},
+	{"encoding.TextMarshaler", []stdMethod{{"MarshalText", nil, []string{"[]byte", "error"}}}},
+	{"encoding.TextUnmarshaler", []stdMethod{{"UnmarshalText", []string{"[]byte"}, []string{"error"}}}},
+	{"encoding.BinaryMarshaler", []stdMethod{{"MarshalBinary", nil, []string{"[]byte", "error"}}}},
+	{"encoding.BinaryUnmarshaler", []stdMethod{{"UnmarshalBinary", []string{"[]byte"}, []string{"error"}}}},
+	{"encoding/json.Marshaler", []stdMethod{{"MarshalJSON", nil, []string{"[]byte", "error"}}}},
+	{"encoding/json.Unmarshaler", []stdMethod{{"UnmarshalJSON", []string{"[]byte"}, []string{"error"}}}},
+	{"sort.Interface", []stdMethod{
+		{"Len", nil, []string{"int"}},
+		{"Less", []string{"int", "int"}, []string{"bool"}},
+		{"Swap", []string{"int", "int"}, nil},
+	}},
+}
+
+// lostInterfaces returns the standard interfaces implemented by a type with
+// the previous methods that are not implemented with the current ones.
+func lostInterfaces(prev, current []Func) []Change {
+	var changes []Change
+	prevMethods, currentMethods := funcsIndex(prev), funcsIndex(current)
+	for _, iface := range stdInterfaces {
+		if iface.implementedBy(prevMethods) && !iface.implementedBy(currentMethods) {
+			changes = append(changes, InterfaceLost{iface.name, iface.missing(currentMethods)})
+		}
+	}
+	return changes
+}
+
+func (i stdInterface) implementedBy(methods map[string]Func) bool {
+	return len(i.missing(methods)) == 0
+}
+
+// missing returns the names of the methods of the interface that are not
+// in the given methods with the same signature.
+func (i stdInterface) missing(methods map[string]Func) []string {
+	var result []string
+	for _, m := range i.methods {
+		f, ok := methods[m.name]
+		if !ok || !paramTypesAre(f.Args, m.args) || !paramTypesAre(f.Return, m.results) {
+			result = append(result, m.name)
+		}
+	}
+	return result
+}
+
+func paramTypesAre(params []Param, types []string) bool {
+	if len(params) != len(types) {
+		return false
+	}
+
+	for i, p := range params {
+		if typeString(p.Type) != types[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package semverlint
+
+import "testing"
+
+func TestLostInterfaces(t *testing.T) {
+	prev := `
+type File struct{}
+
+func (File) Read(p []byte) (int, error) { return 0, nil }
+func (File) Close() error { return nil }
+
+type Size int
+
+func (Size) String() string { return "" }
+
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+`
+	current := `
+type File struct{}
+
+func (File) Close() error { return nil }
+
+type Size int
+
+func (Size) String() string { return "" }
+
+type Reader interface {
+	Read(p []byte) (n int64, err error)
+}
+`
+
+	AssertChanges(t, diffSourcesWithOptions(t, prev, current, DiffOptions{StdInterfaces: true}), []string{
+		`example.com/m: interface Reader: method Read: result with type int64 at position 0: type changed from "int" to "int64", existing implementations no longer satisfy the interface`,
+		`example.com/m: interface Reader: no longer implements io.Reader, missing Read`,
+		`example.com/m: struct File: method Read: was removed`,
+		`example.com/m: struct File: no longer implements io.Reader, missing Read`,
+	})
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: interface Reader: method Read: result with type int64 at position 0: type changed from "int" to "int64", existing implementations no longer satisfy the interface`,
+		`example.com/m: struct File: method Read: was removed`,
+	})
+}
+
+func TestLostMultiMethodInterfaces(t *testing.T) {
+	prev := `
+type Items []int
+
+func (i Items) Len() int           { return len(i) }
+func (i Items) Less(a, b int) bool { return i[a] < i[b] }
+func (i Items) Swap(a, b int)      { i[a], i[b] = i[b], i[a] }
+
+type Pool struct{}
+
+func (Pool) Len() int           { return 0 }
+func (Pool) Less(a, b int) bool { return false }
+func (Pool) Swap(a, b int)      {}
+`
+	current := `
+type Items []int
+
+func (i Items) Len() int           { return len(i) }
+func (i Items) Less(a, b int) bool { return i[a] < i[b] }
+
+type Pool struct{}
+
+func (Pool) Len() int           { return 0 }
+func (Pool) Less(a, b int) bool { return false }
+`
+
+	AssertChanges(t, diffSourcesWithOptions(t, prev, current, DiffOptions{StdInterfaces: true}), []string{
+		`example.com/m: struct Pool: method Swap: was removed`,
+		`example.com/m: struct Pool: no longer implements sort.Interface, missing Swap`,
+		`example.com/m: type definition Items: method Swap: was removed`,
+		`example.com/m: type definition Items: no longer implements sort.Interface, missing Swap`,
+	})
+}
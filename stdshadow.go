@@ -0,0 +1,59 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// StdNameShadowed is reported along with the addition of a declaration named
+// after a well-known type of the standard library, which users may confuse
+// with it, e.g. a type Context in a package that also uses context.Context.
+// It's advisory, see DiffOptions.StdShadowing.
+type StdNameShadowed struct {
+	Type string
+}
+
+func (s StdNameShadowed) String() string {
+	return fmt.Sprintf("has the same name as %s of the standard library, which may be confusing", s.Type)
+}
+
+// stdTypes are the well-known types of the standard library by name.
+var stdTypes = map[string]string{
+	"Context":     "context.Context",
+	"Reader":      "io.Reader",
+	"Writer":      "io.Writer",
+	"Closer":      "io.Closer",
+	"ReadCloser":  "io.ReadCloser",
+	"WriteCloser": "io.WriteCloser",
+	"ReadWriter":  "io.ReadWriter",
+	"Stringer":    "fmt.Stringer",
+	"Time":        "time.Time",
+	"Duration":    "time.Duration",
+	"Handler":     "net/http.Handler",
+	"Marshaler":   "encoding/json.Marshaler",
+	"Unmarshaler": "encoding/json.Unmarshaler",
+}
+
+// stdShadowing annotates the added and renamed exported declarations named
+// after a well-known type of the standard library with StdNameShadowed.
+func stdShadowing(changes []Change) []Change {
+	for i, c := range changes {
+		d, ok := c.(DeclChange)
+		if !ok || d.Type == PackageType || len(d.Changes) == 0 {
+			continue
+		}
+
+		name := d.Name
+		if r, ok := d.Changes[0].(Renamed); ok {
+			name = r.To
+		} else if !isDeclChange(d, Added{}) {
+			continue
+		}
+
+		if t, ok := stdTypes[name]; ok && ast.IsExported(name) {
+			d.Changes = append(d.Changes[:len(d.Changes):len(d.Changes)], StdNameShadowed{t})
+			changes[i] = d
+		}
+	}
+	return changes
+}
@@ -0,0 +1,43 @@
+package semverlint
+
+import "testing"
+
+func TestStdShadowing(t *testing.T) {
+	prev := `
+type Existing struct{}
+`
+	current := `
+type Existing struct{}
+
+type Context struct{}
+
+type Reader interface{ Read() }
+
+func Stringer() {}
+
+type Config struct{}
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: function Stringer: was added`,
+		`example.com/m: interface Reader: was added`,
+		`example.com/m: struct Config: was added`,
+		`example.com/m: struct Context: was added`,
+	})
+
+	changes := diffSourcesWithOptions(t, prev, current, DiffOptions{StdShadowing: true})
+	AssertChanges(t, changes, []string{
+		`example.com/m: function Stringer: was added, has the same name as fmt.Stringer of the standard library, which may be confusing`,
+		`example.com/m: interface Reader: was added, has the same name as io.Reader of the standard library, which may be confusing`,
+		`example.com/m: struct Config: was added`,
+		`example.com/m: struct Context: was added, has the same name as context.Context of the standard library, which may be confusing`,
+	})
+
+	// The notes are advisory, they don't require a bigger bump.
+	if bump := Recommend(changes); bump != MinorBump {
+		t.Errorf("expected a minor bump, got %s", bump)
+	}
+
+	// Declarations that already existed are not noted again.
+	AssertChanges(t, diffSourcesWithOptions(t, current, current, DiffOptions{StdShadowing: true}), nil)
+}
@@ -0,0 +1,189 @@
+package semverlint
+
+import "fmt"
+
+// Summary is the number of changes of each severity.
+type Summary struct {
+	Breaking int `json:"breaking"`
+	Additive int `json:"additive"`
+	Cosmetic int `json:"cosmetic"`
+}
+
+func (s *Summary) add(c Change) {
+	switch SeverityOf(c) {
+	case Breaking:
+		s.Breaking++
+	case Additive:
+		s.Additive++
+	case Cosmetic:
+		s.Cosmetic++
+	}
+}
+
+// PackageSummary is the summary of the changes of a single package.
+type PackageSummary struct {
+	Path string `json:"path"`
+	Summary
+}
+
+// Summarize returns the number of changes of each severity across all
+// packages. Only top-level changes are counted, that is, a declaration with
+// several changes counts once with its highest severity.
+func (c APIChanges) Summarize() Summary {
+	var s Summary
+	c.Walk(func(_ PackageChanges, change Change) bool {
+		s.add(change)
+		return false
+	})
+	return s
+}
+
+// PackageSummaries returns the summary of the changes of each package, in
+// the same order as the packages. Changes are counted as in Summarize.
+func (c APIChanges) PackageSummaries() []PackageSummary {
+	var result = make([]PackageSummary, len(c))
+	for i, pkg := range c {
+		result[i].Path = pkg.Path
+		APIChanges{pkg}.Walk(func(_ PackageChanges, change Change) bool {
+			result[i].add(change)
+			return false
+		})
+	}
+	return result
+}
+
+// Weights of the breaking top-level changes when computing the share of the
+// API they break.
+const (
+	removalWeight  = 2
+	breakingWeight = 1
+)
+
+// Score returns a compatibility score between 0 and 100 for the changes
+// made to the given previous API, meant to communicate the stability of a
+// release at a glance. The score depends on the most severe kind of change
+// and on the share of the API that changed, so the same changes weigh less
+// in a bigger API:
+//
+//   - 100 means there are no changes other than cosmetic ones.
+//   - 90 to 99 means the API was only extended. Additions weigh lightly,
+//     so the score never goes below 90 because of them.
+//   - 0 to 89 means there are breaking changes: it's lower the larger the
+//     share of the declarations of the API that were broken, where
+//     removals weigh twice as much as other breaking changes. Removing the
+//     whole API scores 0.
+//
+// The previous API is needed because the changes alone don't tell the size
+// of the API they were made to: unchanged declarations are not part of
+// them, so a single removal would weigh the same in an API of two
+// declarations and in one of two hundred.
+func (c APIChanges) Score(prev API) int {
+	var pkgs = make(map[string]Package, len(prev))
+	var size int
+	for _, p := range prev {
+		pkgs[p.Path] = p
+		size += declCount(p)
+	}
+
+	if size == 0 {
+		size = 1
+	}
+
+	var breaking, additive int
+	c.Walk(func(pkg PackageChanges, change Change) bool {
+		switch SeverityOf(change) {
+		case Breaking:
+			switch d, _ := change.(DeclChange); {
+			case d.Type == PackageType && isRemoval(d) && declCount(pkgs[pkg.Path]) > 0:
+				// All the declarations of the package were removed.
+				breaking += removalWeight * declCount(pkgs[pkg.Path])
+			case isRemoval(change):
+				breaking += removalWeight
+			default:
+				breaking += breakingWeight
+			}
+		case Additive:
+			additive++
+		}
+		return false
+	})
+
+	switch {
+	case breaking > 0:
+		broken := breaking * 89 / (removalWeight * size)
+		if broken > 89 {
+			broken = 89
+		}
+		return 89 - broken
+	case additive > 0:
+		added := additive * 9 / size
+		if added > 9 {
+			added = 9
+		}
+		return 99 - added
+	default:
+		return 100
+	}
+}
+
+// declCount returns the number of top-level declarations of the package.
+func declCount(p Package) int {
+	return len(p.Vars) + len(p.Consts) + len(p.Funcs) + len(p.Structs) + len(p.Interfaces) + len(p.Types)
+}
+
+// isRemoval reports whether the change is the removal of a declaration.
+func isRemoval(c Change) bool {
+	d, ok := c.(DeclChange)
+	return ok && len(d.Changes) > 0 && d.Changes[0] == (Removed{})
+}
+
+// maxBreakingReasons is the maximum number of reasons returned by
+// BreakingReasons.
+const maxBreakingReasons = 10
+
+// BreakingReasons returns a short description of each breaking top-level
+// change, e.g. "removed function pkg.Foo" or "changed struct pkg.Bar", to
+// be used in space-constrained messages such as commit statuses. At most
+// 10 reasons are returned, followed by the number of the remaining ones,
+// e.g. "and 3 more".
+func BreakingReasons(changes APIChanges) []string {
+	var reasons []string
+	var seen = make(map[string]struct{})
+	changes.Walk(func(pkg PackageChanges, c Change) bool {
+		if !IsBreaking(c) {
+			return false
+		}
+
+		reason := breakingReason(pkg, c)
+		if _, ok := seen[reason]; !ok {
+			seen[reason] = struct{}{}
+			reasons = append(reasons, reason)
+		}
+		return false
+	})
+
+	if len(reasons) > maxBreakingReasons {
+		more := len(reasons) - maxBreakingReasons
+		reasons = append(reasons[:maxBreakingReasons], fmt.Sprintf("and %d more", more))
+	}
+	return reasons
+}
+
+// breakingReason returns the short description of a breaking top-level
+// change of the given package.
+func breakingReason(pkg PackageChanges, c Change) string {
+	d, ok := c.(DeclChange)
+	if !ok {
+		return c.String()
+	}
+
+	name := pkg.Name + "." + d.Name
+	if d.Type == PackageType {
+		name = pkg.Path
+	}
+
+	if isRemoval(d) {
+		return fmt.Sprintf("removed %s %s", d.Type, name)
+	}
+	return fmt.Sprintf("changed %s %s", d.Type, name)
+}
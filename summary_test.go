@@ -0,0 +1,188 @@
+package semverlint
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPackageSummaries(t *testing.T) {
+	prev := moduleAPI(t, map[string]string{
+		"a/a.go": "package a\n\nfunc A() {}\n\nfunc B() {}\n",
+		"b/b.go": "package b\n\nfunc C() {}\n",
+	})
+	current := moduleAPI(t, map[string]string{
+		"a/a.go": "package a\n\nfunc A(n int) {}\n\nfunc D() {}\n",
+		"b/b.go": "package b\n\nfunc C() {}\n\nfunc E() {}\n",
+	})
+
+	got := Diff(current, prev).PackageSummaries()
+	want := []PackageSummary{
+		{Path: "example.com/m/a", Summary: Summary{Breaking: 2, Additive: 1}},
+		{Path: "example.com/m/b", Summary: Summary{Additive: 1}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestScore(t *testing.T) {
+	prev := moduleAPI(t, map[string]string{
+		"a/a.go": "package a\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n\nconst E = 1\n",
+		"b/b.go": "package b\n\nfunc F() {}\n\nfunc G() {}\n\nfunc H() {}\n\nfunc I() {}\n\nvar J int\n",
+	})
+
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		score    int
+		min, max int
+	}{
+		{
+			"unchanged",
+			map[string]string{
+				"a/a.go": "package a\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n\nconst E = 1\n",
+				"b/b.go": "package b\n\nfunc F() {}\n\nfunc G() {}\n\nfunc H() {}\n\nfunc I() {}\n\nvar J int\n",
+			},
+			100, 100, 100,
+		},
+		{
+			"one addition",
+			map[string]string{
+				"a/a.go": "package a\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n\nconst E = 1\n\nfunc K() {}\n",
+				"b/b.go": "package b\n\nfunc F() {}\n\nfunc G() {}\n\nfunc H() {}\n\nfunc I() {}\n\nvar J int\n",
+			},
+			99, 90, 99,
+		},
+		{
+			"new package",
+			map[string]string{
+				"a/a.go": "package a\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n\nconst E = 1\n",
+				"b/b.go": "package b\n\nfunc F() {}\n\nfunc G() {}\n\nfunc H() {}\n\nfunc I() {}\n\nvar J int\n",
+				"c/c.go": "package c\n\nfunc K() {}\n\nfunc L() {}\n\nfunc M() {}\n",
+			},
+			99, 90, 99,
+		},
+		{
+			"one breaking change",
+			map[string]string{
+				"a/a.go": "package a\n\nfunc A(n int) {}\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n\nconst E = 1\n",
+				"b/b.go": "package b\n\nfunc F() {}\n\nfunc G() {}\n\nfunc H() {}\n\nfunc I() {}\n\nvar J int\n",
+			},
+			85, 50, 89,
+		},
+		{
+			"one removal",
+			map[string]string{
+				"a/a.go": "package a\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n\nconst E = 1\n",
+				"b/b.go": "package b\n\nfunc F() {}\n\nfunc G() {}\n\nfunc H() {}\n\nfunc I() {}\n\nvar J int\n",
+			},
+			81, 50, 89,
+		},
+		{
+			"package removed",
+			map[string]string{
+				"a/a.go": "package a\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n\nconst E = 1\n",
+			},
+			45, 0, 49,
+		},
+		{
+			"everything removed",
+			map[string]string{
+				"c/c.go": "package c\n\nfunc K() {}\n",
+			},
+			0, 0, 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			score := Diff(moduleAPI(t, tc.files), prev).Score(prev)
+			if score != tc.score {
+				t.Errorf("expected score %d, got %d", tc.score, score)
+			}
+
+			if score < tc.min || score > tc.max {
+				t.Errorf("expected score between %d and %d, got %d", tc.min, tc.max, score)
+			}
+		})
+	}
+}
+
+func TestScoreAPISize(t *testing.T) {
+	small := sourceAPI(t, "func A() {}\n\nfunc B() {}")
+	large := sourceAPI(t, "func A() {}\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n\nfunc E() {}\n\nfunc F() {}")
+
+	smallScore := Diff(sourceAPI(t, "func B() {}"), small).Score(small)
+	largeScore := Diff(sourceAPI(t, "func B() {}\n\nfunc C() {}\n\nfunc D() {}\n\nfunc E() {}\n\nfunc F() {}"), large).Score(large)
+	if smallScore >= largeScore {
+		t.Errorf("expected removing a function to score lower in a smaller API, got %d and %d", smallScore, largeScore)
+	}
+}
+
+func TestBreakingReasons(t *testing.T) {
+	prev := `
+type Bar struct{ A int }
+
+func Foo() {}
+
+func Baz(a int) {}
+
+func Qux() {}
+`
+	current := `
+type Bar struct {
+	A int
+	B string
+}
+
+func Baz(a string) {}
+
+func Qux() {}
+
+func New() {}
+`
+
+	changes := diffSources(t, prev, current)
+	reasons := BreakingReasons(changes)
+	want := []string{"changed function m.Baz", "removed function m.Foo"}
+	if !reflect.DeepEqual(reasons, want) {
+		t.Errorf("expected reasons %q, got %q", want, reasons)
+	}
+
+	var breaking int
+	changes.Walk(func(_ PackageChanges, c Change) bool {
+		if IsBreaking(c) {
+			breaking++
+		}
+		return false
+	})
+	if breaking != len(reasons) {
+		t.Errorf("expected a reason for each of the %d breaking changes, got %d", breaking, len(reasons))
+	}
+
+	if reasons := BreakingReasons(diffSources(t, current, current)); len(reasons) != 0 {
+		t.Errorf("expected no reasons without changes, got %q", reasons)
+	}
+}
+
+func TestBreakingReasonsLimit(t *testing.T) {
+	var prev string
+	for i := 0; i < maxBreakingReasons+3; i++ {
+		prev += fmt.Sprintf("func F%02d() {}\n\n", i)
+	}
+
+	reasons := BreakingReasons(diffSources(t, prev, ""))
+	if len(reasons) != maxBreakingReasons+1 {
+		t.Fatalf("expected %d reasons, got %q", maxBreakingReasons+1, reasons)
+	}
+
+	if reasons[0] != "removed function m.F00" {
+		t.Errorf("expected the first reason to be the removal of F00, got %q", reasons[0])
+	}
+
+	if last := reasons[maxBreakingReasons]; last != "and 3 more" {
+		t.Errorf("expected the last reason to be %q, got %q", "and 3 more", last)
+	}
+}
@@ -0,0 +1,65 @@
+package semverlint
+
+import (
+	"fmt"
+)
+
+// VersionDiff is the difference between the APIs of two versions.
+type VersionDiff struct {
+	From    Version
+	To      Version
+	Changes APIChanges
+}
+
+// SweepHistory returns the differences between the APIs of each pair of
+// consecutive releases of the repository at the given path, in semver
+// order, which is the whole history of its API, e.g. to generate a
+// changelog. HEAD is not included.
+func SweepHistory(path string) ([]VersionDiff, error) {
+	return SweepHistoryWithProgress(path, nil)
+}
+
+// SweepHistoryWithProgress is like SweepHistory, but calls progress, if not
+// nil, after getting the API of each release with the number of releases
+// done so far and the total number of releases, since getting the API of
+// many releases can take a while.
+func SweepHistoryWithProgress(path string, progress func(v Version, done, total int)) ([]VersionDiff, error) {
+	versions, err := Versions(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Versions are already sorted, with HEAD first.
+	var releases []Version
+	for _, v := range versions {
+		if v.Name != headVersion {
+			releases = append(releases, v)
+		}
+	}
+
+	var result []VersionDiff
+	var prev API
+	for i, v := range releases {
+		// The API of each release is the current API of a step and the
+		// previous API of the next one, so it's only loaded once.
+		api, err := VersionAPI(path, v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get API of version %s: %s", v.Name, err)
+		}
+
+		if progress != nil {
+			progress(v, i+1, len(releases))
+		}
+
+		if i > 0 {
+			result = append(result, VersionDiff{
+				From:    releases[i-1],
+				To:      v,
+				Changes: Diff(api, prev),
+			})
+		}
+		prev = api
+	}
+
+	return result, nil
+}
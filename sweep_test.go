@@ -0,0 +1,66 @@
+package semverlint
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSweepHistory(t *testing.T) {
+	r := newTestRepo(t)
+	r.tag("v1.9.0", r.commit(map[string]string{"m.go": packageSource(`func F() {}`)}))
+	r.tag("v1.10.0", r.commit(map[string]string{"m.go": packageSource(`
+func F() {}
+
+func G() {}
+`)}))
+	r.tag("v2.0.0", r.commit(map[string]string{"m.go": packageSource(`func G() {}`)}))
+	// Changes after the last release are not part of the history.
+	r.commit(map[string]string{"m.go": packageSource(`func H() {}`)})
+
+	var progress []string
+	diffs, err := SweepHistoryWithProgress(r.dir, func(v Version, done, total int) {
+		progress = append(progress, fmt.Sprintf("%s %d/%d", v.Name, done, total))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"v1.9.0 1/3", "v1.10.0 2/3", "v2.0.0 3/3"}; !reflect.DeepEqual(progress, want) {
+		t.Errorf("expected progress %v, got %v", want, progress)
+	}
+
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 step diffs, got %d", len(diffs))
+	}
+
+	steps := []struct {
+		from, to string
+		want     []string
+	}{
+		{"v1.9.0", "v1.10.0", []string{"example.com/m: function G: was added"}},
+		{"v1.10.0", "v2.0.0", []string{"example.com/m: function F: was removed"}},
+	}
+
+	for i, s := range steps {
+		d := diffs[i]
+		if d.From.Name != s.from || d.To.Name != s.to {
+			t.Errorf("expected step %d to be %s..%s, got %s..%s", i, s.from, s.to, d.From.Name, d.To.Name)
+		}
+		AssertChanges(t, d.Changes, s.want)
+	}
+}
+
+func TestSweepHistorySingleRelease(t *testing.T) {
+	r := newTestRepo(t)
+	r.tag("v1.0.0", r.commit(map[string]string{"m.go": packageSource(`func F() {}`)}))
+
+	diffs, err := SweepHistory(r.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diffs) != 0 {
+		t.Errorf("expected no step diffs, got %d", len(diffs))
+	}
+}
@@ -0,0 +1,130 @@
+package semverlint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TagOptionChanged is reported when a known option is added to or removed
+// from a key of the tag of a struct field, e.g. omitempty in a json tag.
+// Its severity is configured with DiffOptions.TagOptionSeverity.
+type TagOptionChanged struct {
+	Key      string
+	Option   string
+	Added    bool
+	Severity Severity
+}
+
+func (t TagOptionChanged) String() string {
+	if t.Added {
+		return fmt.Sprintf("option %s added to tag %s", t.Option, t.Key)
+	}
+	return fmt.Sprintf("option %s removed from tag %s", t.Option, t.Key)
+}
+
+// knownTagOptions are the tag options whose changes are reported.
+var knownTagOptions = map[string]struct{}{
+	"omitempty": {},
+	"omitzero":  {},
+	"required":  {},
+}
+
+// validationTagKeys are the tag keys whose values are made only of options,
+// instead of a name followed by options.
+var validationTagKeys = map[string]struct{}{
+	"validate": {},
+	"binding":  {},
+}
+
+// tagOptionsDiff returns the changes of the known options of the given
+// field tags.
+func tagOptionsDiff(prev, current string, severity Severity) []Change {
+	prevOpts, currentOpts := tagOptions(prev), tagOptions(current)
+
+	var keys = make(map[string]struct{})
+	for k := range prevOpts {
+		keys[k] = struct{}{}
+	}
+	for k := range currentOpts {
+		keys[k] = struct{}{}
+	}
+
+	var sortedKeys = make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	var changes []Change
+	for _, k := range sortedKeys {
+		for _, opt := range prevOpts[k] {
+			if !containsString(currentOpts[k], opt) {
+				changes = append(changes, TagOptionChanged{k, opt, false, severity})
+			}
+		}
+
+		for _, opt := range currentOpts[k] {
+			if !containsString(prevOpts[k], opt) {
+				changes = append(changes, TagOptionChanged{k, opt, true, severity})
+			}
+		}
+	}
+	return changes
+}
+
+// tagOptions returns the known options of each key of the given tag, which
+// follows the conventional format of reflect.StructTag.
+func tagOptions(tag string) map[string][]string {
+	var result = make(map[string][]string)
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		i := strings.Index(tag, `:"`)
+		if i <= 0 {
+			break
+		}
+
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// Find the end of the quoted value, skipping escaped quotes.
+		j := 1
+		for j < len(tag) && tag[j] != '"' {
+			if tag[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(tag) {
+			break
+		}
+
+		value, err := strconv.Unquote(tag[:j+1])
+		tag = tag[j+1:]
+		if err != nil {
+			continue
+		}
+
+		parts := strings.Split(value, ",")
+		if _, ok := validationTagKeys[key]; !ok {
+			parts = parts[1:]
+		}
+
+		for _, p := range parts {
+			if _, ok := knownTagOptions[p]; ok {
+				result[key] = append(result[key], p)
+			}
+		}
+	}
+	return result
+}
+
+func containsString(strs []string, s string) bool {
+	for _, x := range strs {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
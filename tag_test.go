@@ -0,0 +1,48 @@
+package semverlint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagOptionChanged(t *testing.T) {
+	prev := "type Config struct {\n" +
+		"\tName string `json:\"name,omitempty\" yaml:\"name\"`\n" +
+		"\tPort int `json:\"port\" validate:\"min=1\"`\n" +
+		"}"
+	current := "type Config struct {\n" +
+		"\tName string `json:\"name\" yaml:\"name,required\"`\n" +
+		"\tPort int `json:\"port,omitempty\" validate:\"required,min=1\"`\n" +
+		"}"
+
+	want := []string{
+		`example.com/m: struct Config: field "Name" at position 0: option omitempty removed from tag json, option required added to tag yaml, field "Port" at position 1: option omitempty added to tag json, option required added to tag validate`,
+	}
+
+	changes := diffSourcesWithOptions(t, prev, current, DiffOptions{TagOptionSeverity: Breaking})
+	AssertChanges(t, changes, want)
+	if r := Recommend(changes); r != MajorBump {
+		t.Errorf("expected a major bump with breaking tag options, got %s", r)
+	}
+
+	changes = diffSourcesWithOptions(t, prev, current, DiffOptions{TagOptionSeverity: Additive})
+	AssertChanges(t, changes, want)
+	if r := Recommend(changes); r != MinorBump {
+		t.Errorf("expected a minor bump with additive tag options, got %s", r)
+	}
+
+	AssertChanges(t, diffSources(t, prev, current), nil)
+}
+
+func TestTagOptions(t *testing.T) {
+	got := tagOptions(`json:"a,omitempty,string" xml:"b" validate:"required,min=1" yaml:"c,omitzero,inline"`)
+	want := map[string][]string{
+		"json":     {"omitempty"},
+		"validate": {"required"},
+		"yaml":     {"omitzero"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,193 @@
+package semverlint
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Transformer post-processes the changes computed by a diff, e.g. to
+// suppress, reclassify or enrich changes.
+type Transformer func(APIChanges) APIChanges
+
+// BreakingOnly is a transformer that only keeps the breaking changes.
+func BreakingOnly(changes APIChanges) APIChanges {
+	return filterChanges(changes, func(_ PackageChanges, c Change) bool {
+		return IsBreaking(c)
+	})
+}
+
+// IgnoreDecls returns a transformer that drops the changes to the given
+// declarations, written as the package path followed by a dot and the
+// name of the declaration, e.g. github.com/me/mod/pkg.Foo.
+func IgnoreDecls(decls ...string) Transformer {
+	var ignored = make(map[string]struct{}, len(decls))
+	for _, d := range decls {
+		ignored[d] = struct{}{}
+	}
+
+	return func(changes APIChanges) APIChanges {
+		return filterChanges(changes, func(pkg PackageChanges, c Change) bool {
+			d, ok := c.(DeclChange)
+			if !ok {
+				return true
+			}
+
+			_, ok = ignored[pkg.Path+"."+d.Name]
+			return !ok
+		})
+	}
+}
+
+// ExcludeSymbols returns a transformer that drops the changes to the given
+// declarations or methods, written as the package path followed by a dot and
+// the name of the declaration and, for methods, another dot and the name of
+// the method, e.g. github.com/me/mod/pkg.Foo or github.com/me/mod/pkg.Foo.Bar.
+// Declarations with only changes to excluded methods are dropped as well.
+func ExcludeSymbols(symbols ...string) Transformer {
+	var excluded = make(map[string]struct{}, len(symbols))
+	for _, s := range symbols {
+		excluded[s] = struct{}{}
+	}
+
+	return func(changes APIChanges) APIChanges {
+		var result = make(APIChanges, len(changes))
+		for i, pkg := range changes {
+			result[i] = PackageChanges{Name: pkg.Name, Path: pkg.Path}
+			for _, c := range pkg.Changes {
+				d, ok := c.(DeclChange)
+				if !ok {
+					result[i].Changes = append(result[i].Changes, c)
+					continue
+				}
+
+				name := pkg.Path + "." + d.Name
+				if _, ok := excluded[name]; ok {
+					continue
+				}
+
+				var kept []Change
+				for _, dc := range d.Changes {
+					if m, ok := dc.(MethodChanged); ok {
+						if _, ok := excluded[name+"."+m.Name]; ok {
+							continue
+						}
+					}
+					kept = append(kept, dc)
+				}
+
+				if len(kept) > 0 {
+					d.Changes = kept
+					result[i].Changes = append(result[i].Changes, d)
+				}
+			}
+		}
+		return result
+	}
+}
+
+// FilterByUsage returns only the changes that affect the given symbols, which
+// are the declarations and methods used by a consumer of the API, written
+// as accepted by ExcludeSymbols, e.g. github.com/me/mod/pkg.Foo or
+// github.com/me/mod/pkg.Foo.Bar, to know whether upgrading to the new
+// version breaks them specifically. Using a method implies using the type
+// it belongs to, so the changes of the type that are not about its methods
+// are kept as well. Changes of whole packages are kept if any of their
+// symbols is used.
+func FilterByUsage(changes APIChanges, usedSymbols []string) APIChanges {
+	var used = make(map[string]struct{}, len(usedSymbols))
+	var usedDecls = make(map[string]struct{}, len(usedSymbols))
+	for _, s := range usedSymbols {
+		used[s] = struct{}{}
+		usedDecls[s] = struct{}{}
+		// The symbol may be a method, in which case its type is used.
+		if i := strings.LastIndex(s, "."); i > strings.LastIndex(s, "/") {
+			usedDecls[s[:i]] = struct{}{}
+		}
+	}
+
+	var result = make(APIChanges, len(changes))
+	for i, pkg := range changes {
+		result[i] = PackageChanges{Name: pkg.Name, Path: pkg.Path}
+		var pkgUsed bool
+		for _, s := range usedSymbols {
+			if strings.HasPrefix(s, pkg.Path+".") {
+				pkgUsed = true
+				break
+			}
+		}
+
+		for _, c := range pkg.Changes {
+			d, ok := c.(DeclChange)
+			if !ok || d.Type == PackageType {
+				if pkgUsed {
+					result[i].Changes = append(result[i].Changes, c)
+				}
+				continue
+			}
+
+			name := pkg.Path + "." + d.Name
+			if _, ok := used[name]; ok {
+				result[i].Changes = append(result[i].Changes, d)
+				continue
+			}
+
+			if _, ok := usedDecls[name]; !ok {
+				continue
+			}
+
+			var kept []Change
+			for _, dc := range d.Changes {
+				if m, ok := dc.(MethodChanged); ok {
+					if _, ok := used[name+"."+m.Name]; !ok {
+						continue
+					}
+				}
+				kept = append(kept, dc)
+			}
+
+			if len(kept) > 0 {
+				d.Changes = kept
+				result[i].Changes = append(result[i].Changes, d)
+			}
+		}
+	}
+	return result
+}
+
+// ReadIgnoreFile reads a file with a declaration to ignore per line, as
+// accepted by IgnoreDecls, and returns the transformer that ignores them.
+// Empty lines and lines starting with # are skipped.
+func ReadIgnoreFile(r io.Reader) (Transformer, error) {
+	var decls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		decls = append(decls, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read ignore file: %s", err)
+	}
+
+	return IgnoreDecls(decls...), nil
+}
+
+// filterChanges returns the changes with only the top-level changes of each
+// package for which keep returns true.
+func filterChanges(changes APIChanges, keep func(PackageChanges, Change) bool) APIChanges {
+	var result = make(APIChanges, len(changes))
+	for i, pkg := range changes {
+		result[i] = PackageChanges{Name: pkg.Name, Path: pkg.Path}
+		for _, c := range pkg.Changes {
+			if keep(pkg, c) {
+				result[i].Changes = append(result[i].Changes, c)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+package semverlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransformers(t *testing.T) {
+	prev := `
+func F() {}
+
+func G() {}
+`
+	current := `
+func F(n int) {}
+
+func H() {}
+`
+
+	ignore, err := ReadIgnoreFile(strings.NewReader("# ignored\n\nexample.com/m.G\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DiffOptions{Transformers: []Transformer{ignore, BreakingOnly}}
+	AssertChanges(t, diffSourcesWithOptions(t, prev, current, opts), []string{
+		"example.com/m: function F: argument n with type int at position 0: was added",
+	})
+}
+
+func TestExcludeSymbols(t *testing.T) {
+	prev := `
+func F() {}
+
+func G() {}
+
+type S struct{ X int }
+
+func (S) Close() error { return nil }
+func (S) Read() {}
+func (S) Write() {}
+
+type I interface {
+	Do()
+	Undo()
+}
+`
+	current := `
+func F(n int) {}
+
+type S struct{ X int64 }
+
+func (S) Close() {}
+func (S) Write(int) {}
+
+type I interface {
+	Undo() error
+}
+`
+
+	opts := DiffOptions{ExcludeSymbols: []string{
+		"example.com/m.F",
+		"example.com/m.S.Close",
+		"example.com/m.S.Read",
+		"example.com/m.I.Do",
+		"example.com/m.I.Undo",
+		"example.com/m.Missing",
+	}}
+
+	AssertChanges(t, diffSourcesWithOptions(t, prev, current, opts), []string{
+		`example.com/m: function G: was removed`,
+		`example.com/m: struct S: field "X" at position 0: type changed from "int" to "int64"`,
+		`example.com/m: struct S: method Write: argument  with type int at position 0: was added`,
+	})
+
+	// Methods can't be excluded without their receiver type.
+	opts = DiffOptions{ExcludeSymbols: []string{"example.com/m.Close", "example.com/m.G"}}
+	AssertChanges(t, diffSourcesWithOptions(t, prev, current, opts), []string{
+		`example.com/m: function F: argument n with type int at position 0: was added`,
+		`example.com/m: interface I: method Do: was removed, method Undo: error result added at position 0, callers must now handle it, existing implementations no longer satisfy the interface`,
+		`example.com/m: struct S: field "X" at position 0: type changed from "int" to "int64"`,
+		`example.com/m: struct S: method Close: error result at position 0 was removed, method Read: was removed, method Write: argument  with type int at position 0: was added`,
+	})
+}
+
+func TestFilterByUsage(t *testing.T) {
+	prev := moduleAPI(t, map[string]string{
+		"m.go": packageSource(`
+func F() {}
+
+func G() {}
+
+type Client struct{ Addr string }
+
+func (Client) Get() {}
+func (Client) Put() {}
+
+type Server struct{}
+`),
+		"a/a.go":   "package a\n\nfunc A() {}\n",
+		"old/o.go": "package old\n\nfunc O() {}\n",
+	})
+
+	current := moduleAPI(t, map[string]string{
+		"m.go": packageSource(`
+func F(n int) {}
+
+type Client struct{ Addr []string }
+
+func (Client) Get(key string) {}
+func (Client) Put(key string) {}
+
+type Server struct{ Port int }
+`),
+		"a/a.go": "package a\n\nfunc A() error { return nil }\n",
+	})
+
+	changes := Diff(current, prev)
+	used := []string{
+		"example.com/m.F",
+		"example.com/m.Client.Get",
+		"example.com/m/old.O",
+	}
+
+	AssertChanges(t, FilterByUsage(changes, used), []string{
+		`example.com/m: function F: argument n with type int at position 0: was added`,
+		`example.com/m: struct Client: field "Addr" at position 0: type changed from "string" to "[]string"`,
+		`example.com/m: struct Client: method Get: argument key with type string at position 0: was added`,
+		`example.com/m/old: package old: was removed`,
+	})
+
+	// Using the whole type keeps the changes of all its methods.
+	AssertChanges(t, FilterByUsage(changes, []string{"example.com/m.Client"}), []string{
+		`example.com/m: struct Client: field "Addr" at position 0: type changed from "string" to "[]string"`,
+		`example.com/m: struct Client: method Get: argument key with type string at position 0: was added, method Put: argument key with type string at position 0: was added`,
+	})
+
+	AssertChanges(t, FilterByUsage(changes, nil), nil)
+}
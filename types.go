@@ -0,0 +1,421 @@
+package semverlint
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// walkType calls fn for the given type and all the types it's composed of
+// until fn returns false. The underlying types of named types are not walked,
+// only their type arguments.
+func walkType(t types.Type, fn func(types.Type) bool) {
+	if t == nil || !fn(t) {
+		return
+	}
+
+	switch t := t.(type) {
+	case *types.Pointer:
+		walkType(t.Elem(), fn)
+	case *types.Slice:
+		walkType(t.Elem(), fn)
+	case *types.Array:
+		walkType(t.Elem(), fn)
+	case *types.Chan:
+		walkType(t.Elem(), fn)
+	case *types.Map:
+		walkType(t.Key(), fn)
+		walkType(t.Elem(), fn)
+	case *types.Signature:
+		for i := 0; i < t.Params().Len(); i++ {
+			walkType(t.Params().At(i).Type(), fn)
+		}
+		for i := 0; i < t.Results().Len(); i++ {
+			walkType(t.Results().At(i).Type(), fn)
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			walkType(t.Field(i).Type(), fn)
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			walkType(t.ExplicitMethod(i).Type(), fn)
+		}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			walkType(t.EmbeddedType(i), fn)
+		}
+	case *types.Union:
+		for i := 0; i < t.Len(); i++ {
+			walkType(t.Term(i).Type(), fn)
+		}
+	case *types.Named:
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			walkType(t.TypeArgs().At(i), fn)
+		}
+	case *types.Alias:
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			walkType(t.TypeArgs().At(i), fn)
+		}
+	}
+}
+
+// unalias returns the given type with all the aliases it's composed of
+// replaced by the types they refer to, because an alias and its target are
+// identical types and can be used interchangeably.
+func unalias(t types.Type) types.Type {
+	switch t := t.(type) {
+	case *types.Alias:
+		return unalias(types.Unalias(t))
+	case *types.Pointer:
+		return types.NewPointer(unalias(t.Elem()))
+	case *types.Slice:
+		return types.NewSlice(unalias(t.Elem()))
+	case *types.Array:
+		return types.NewArray(unalias(t.Elem()), t.Len())
+	case *types.Chan:
+		return types.NewChan(t.Dir(), unalias(t.Elem()))
+	case *types.Map:
+		return types.NewMap(unalias(t.Key()), unalias(t.Elem()))
+	case *types.Signature:
+		// The type parameters of a generic signature are already bound to
+		// it, so it can't be rebuilt.
+		if t.TypeParams().Len() > 0 {
+			return t
+		}
+		return types.NewSignatureType(
+			nil, nil, nil,
+			unaliasTuple(t.Params()),
+			unaliasTuple(t.Results()),
+			t.Variadic(),
+		)
+	case *types.Struct:
+		var fields = make([]*types.Var, t.NumFields())
+		var tags = make([]string, t.NumFields())
+		for i := range fields {
+			f := t.Field(i)
+			fields[i] = types.NewField(f.Pos(), f.Pkg(), f.Name(), unalias(f.Type()), f.Embedded())
+			tags[i] = t.Tag(i)
+		}
+		return types.NewStruct(fields, tags)
+	case *types.Named:
+		if t.TypeArgs().Len() == 0 {
+			return t
+		}
+
+		var args = make([]types.Type, t.TypeArgs().Len())
+		for i := range args {
+			args[i] = unalias(t.TypeArgs().At(i))
+		}
+
+		inst, err := types.Instantiate(nil, t.Origin(), args, false)
+		if err != nil {
+			return t
+		}
+		return inst
+	default:
+		return t
+	}
+}
+
+func unaliasTuple(t *types.Tuple) *types.Tuple {
+	var vars = make([]*types.Var, t.Len())
+	for i := range vars {
+		v := t.At(i)
+		vars[i] = types.NewParam(v.Pos(), v.Pkg(), v.Name(), unalias(v.Type()))
+	}
+	return types.NewTuple(vars...)
+}
+
+// typeKey returns a representation of the type that can be compared across
+// different loads of a project. Aliases are replaced by the types they refer
+// to, and type parameters are represented by their position instead of their
+// name (e.g. $0), so renaming a type parameter does not change the key. The
+// qualifier, if any, is used to write the paths of the packages of named
+// types.
+func typeKey(t types.Type, qualifier types.Qualifier) string {
+	switch t := t.(type) {
+	case *serializedType:
+		return t.key
+	case *types.Pointer:
+		// Pointers to types read back from JSON are not serialized
+		// themselves, see typeJSON.
+		if isSerialized(t.Elem()) {
+			return "*" + typeKey(t.Elem(), qualifier)
+		}
+	}
+
+	return strictTypeKey(unalias(t), qualifier)
+}
+
+// strictTypeKey is like typeKey, but aliases are not replaced by the types
+// they refer to.
+func strictTypeKey(t types.Type, qualifier types.Qualifier) string {
+	var b strings.Builder
+	writeTypeKey(&b, t, qualifier)
+	return b.String()
+}
+
+// writeTypeKey writes the given type to b in the same format used by
+// types.TypeString, except for the type parameters, which are written as
+// their index, e.g. $0, and the names of the parameters and results of
+// signatures, which are omitted because renaming them doesn't change the
+// type.
+func writeTypeKey(b *strings.Builder, t types.Type, qualifier types.Qualifier) {
+	if plainKey(t) {
+		b.WriteString(types.TypeString(t, qualifier))
+		return
+	}
+
+	switch t := t.(type) {
+	case *types.TypeParam:
+		fmt.Fprintf(b, "$%d", t.Index())
+	case *types.Pointer:
+		b.WriteByte('*')
+		writeTypeKey(b, t.Elem(), qualifier)
+	case *types.Slice:
+		b.WriteString("[]")
+		writeTypeKey(b, t.Elem(), qualifier)
+	case *types.Array:
+		fmt.Fprintf(b, "[%d]", t.Len())
+		writeTypeKey(b, t.Elem(), qualifier)
+	case *types.Map:
+		b.WriteString("map[")
+		writeTypeKey(b, t.Key(), qualifier)
+		b.WriteByte(']')
+		writeTypeKey(b, t.Elem(), qualifier)
+	case *types.Chan:
+		var parens bool
+		switch t.Dir() {
+		case types.SendRecv:
+			b.WriteString("chan ")
+			// chan (<-chan T) requires parentheses.
+			if c, ok := t.Elem().(*types.Chan); ok && c.Dir() == types.RecvOnly {
+				parens = true
+			}
+		case types.SendOnly:
+			b.WriteString("chan<- ")
+		case types.RecvOnly:
+			b.WriteString("<-chan ")
+		}
+
+		if parens {
+			b.WriteByte('(')
+		}
+		writeTypeKey(b, t.Elem(), qualifier)
+		if parens {
+			b.WriteByte(')')
+		}
+	case *types.Signature:
+		b.WriteString("func")
+		writeSignatureKey(b, t, qualifier)
+	case *types.Struct:
+		b.WriteString("struct{")
+		for i := 0; i < t.NumFields(); i++ {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+
+			f := t.Field(i)
+			if !f.Embedded() {
+				b.WriteString(f.Name() + " ")
+			}
+			writeTypeKey(b, f.Type(), qualifier)
+			if tag := t.Tag(i); tag != "" {
+				b.WriteString(" " + strconv.Quote(tag))
+			}
+		}
+		b.WriteByte('}')
+	case *types.Interface:
+		if t.IsImplicit() && t.NumExplicitMethods() == 0 && t.NumEmbeddeds() == 1 {
+			writeTypeKey(b, t.EmbeddedType(0), qualifier)
+			return
+		}
+
+		b.WriteString("interface{")
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+
+			m := t.ExplicitMethod(i)
+			b.WriteString(m.Name())
+			writeSignatureKey(b, m.Type().(*types.Signature), qualifier)
+		}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			if i > 0 || t.NumExplicitMethods() > 0 {
+				b.WriteString("; ")
+			}
+			writeTypeKey(b, t.EmbeddedType(i), qualifier)
+		}
+		b.WriteByte('}')
+	case *types.Union:
+		for i := 0; i < t.Len(); i++ {
+			if i > 0 {
+				b.WriteString(" | ")
+			}
+
+			term := t.Term(i)
+			if term.Tilde() {
+				b.WriteByte('~')
+			}
+			writeTypeKey(b, term.Type(), qualifier)
+		}
+	case *types.Named:
+		writeTypeNameKey(b, t.Obj(), t.TypeArgs(), qualifier)
+	case *types.Alias:
+		writeTypeNameKey(b, t.Obj(), t.TypeArgs(), qualifier)
+	default:
+		b.WriteString(types.TypeString(t, qualifier))
+	}
+}
+
+func writeSignatureKey(b *strings.Builder, sig *types.Signature, qualifier types.Qualifier) {
+	if sig.TypeParams().Len() > 0 {
+		b.WriteByte('[')
+		var prev types.Type
+		for i := 0; i < sig.TypeParams().Len(); i++ {
+			tp := sig.TypeParams().At(i)
+			if i > 0 {
+				// Consecutive type parameters with the same constraint
+				// share it, e.g. [K, V any].
+				if tp.Constraint() != prev {
+					b.WriteByte(' ')
+					writeTypeKey(b, prev, qualifier)
+				}
+				b.WriteString(", ")
+			}
+			prev = tp.Constraint()
+			writeTypeKey(b, tp, qualifier)
+		}
+		b.WriteByte(' ')
+		writeTypeKey(b, prev, qualifier)
+		b.WriteByte(']')
+	}
+
+	writeTupleKey(b, sig.Params(), sig.Variadic(), qualifier)
+
+	n := sig.Results().Len()
+	if n == 0 {
+		return
+	}
+
+	b.WriteByte(' ')
+	if n == 1 {
+		writeTypeKey(b, sig.Results().At(0).Type(), qualifier)
+		return
+	}
+	writeTupleKey(b, sig.Results(), false, qualifier)
+}
+
+func writeTupleKey(b *strings.Builder, tuple *types.Tuple, variadic bool, qualifier types.Qualifier) {
+	b.WriteByte('(')
+	for i := 0; i < tuple.Len(); i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		v := tuple.At(i)
+		if s, ok := v.Type().(*types.Slice); ok && variadic && i == tuple.Len()-1 {
+			b.WriteString("...")
+			writeTypeKey(b, s.Elem(), qualifier)
+		} else {
+			writeTypeKey(b, v.Type(), qualifier)
+		}
+	}
+	b.WriteByte(')')
+}
+
+func writeTypeNameKey(b *strings.Builder, obj *types.TypeName, args *types.TypeList, qualifier types.Qualifier) {
+	if pkg := obj.Pkg(); pkg != nil {
+		path := pkg.Path()
+		if qualifier != nil {
+			path = qualifier(pkg)
+		}
+
+		if path != "" {
+			b.WriteString(path + ".")
+		}
+	}
+	b.WriteString(obj.Name())
+
+	if args.Len() > 0 {
+		b.WriteByte('[')
+		for i := 0; i < args.Len(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeTypeKey(b, args.At(i), qualifier)
+		}
+		b.WriteByte(']')
+	}
+}
+
+// plainKey reports whether the key of the given type is the same as the
+// string returned by types.TypeString, which is the case of the types
+// without type parameters or signatures.
+func plainKey(t types.Type) bool {
+	var plain = true
+	walkType(t, func(t types.Type) bool {
+		switch t.(type) {
+		case *types.TypeParam, *types.Signature:
+			plain = false
+		}
+		return plain
+	})
+	return plain
+}
+
+// unexportedRefs returns the names of the unexported named types referenced
+// by the given type, which users of the type can't refer to.
+func unexportedRefs(t types.Type) []string {
+	var result []string
+	walkType(t, func(t types.Type) bool {
+		if s, ok := t.(*serializedType); ok {
+			result = append(result, s.unexported...)
+		}
+
+		if n, ok := t.(*types.Named); ok && !n.Obj().Exported() && n.Obj().Pkg() != nil {
+			result = append(result, n.Obj().Pkg().Path()+"."+n.Obj().Name())
+		}
+		return true
+	})
+	return result
+}
+
+// unexportedTypeChanges returns an UnexportedTypeReferenced change for each
+// unexported named type referenced by the current type but not by the
+// previous one.
+func unexportedTypeChanges(prev, current types.Type) []Change {
+	var before = make(map[string]struct{})
+	for _, name := range unexportedRefs(prev) {
+		before[name] = struct{}{}
+	}
+
+	var changes []Change
+	for _, name := range unexportedRefs(current) {
+		if _, ok := before[name]; !ok {
+			before[name] = struct{}{}
+			changes = append(changes, UnexportedTypeReferenced{name})
+		}
+	}
+	return changes
+}
+
+// namedPath returns the name of the given named type qualified by the path of
+// its package, if any, e.g. github.com/me/mod/pkg.Foo.
+func namedPath(t types.Type) (string, bool) {
+	if s, ok := t.(*serializedType); ok {
+		return s.named, s.named != ""
+	}
+
+	n, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return "", false
+	}
+
+	if n.Obj().Pkg() == nil {
+		return n.Obj().Name(), true
+	}
+	return n.Obj().Pkg().Path() + "." + n.Obj().Name(), true
+}
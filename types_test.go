@@ -0,0 +1,55 @@
+package semverlint
+
+import "testing"
+
+func TestTypeParamRenamed(t *testing.T) {
+	prev := `
+type Box[T any] struct{ T T }
+
+func (b Box[T]) Get() T { return b.T }
+
+type Other struct{}
+
+func F[m comparable](x m, y map[m]Other) {}
+
+func G[T any](x struct{ T T }) {}
+`
+	current := `
+type Box[E any] struct{ T E }
+
+func (b Box[E]) Get() E { return b.T }
+
+type Other struct{}
+
+func F[n comparable](x n, y map[n]Other) {}
+
+func G[E any](x struct{ T E }) {}
+`
+
+	AssertChanges(t, diffSources(t, prev, current), nil)
+}
+
+func TestAliasSubstitution(t *testing.T) {
+	prev := `
+type ID = string
+
+type Key string
+
+func Get(id ID, keys []ID) map[ID]Key { return nil }
+
+func Put(k Key) {}
+`
+	current := `
+type ID = string
+
+type Key string
+
+func Get(id string, keys []string) map[string]Key { return nil }
+
+func Put(k string) {}
+`
+
+	AssertChanges(t, diffSources(t, prev, current), []string{
+		`example.com/m: function Put: argument k with type string at position 0: type changed from "example.com/m.Key" to "string"`,
+	})
+}
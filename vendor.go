@@ -0,0 +1,64 @@
+package semverlint
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DiffVendored computes the difference between the API of a vendored
+// dependency and the API of an upstream copy of it, to predict whether
+// upgrading the dependency will break the project vendoring it. The vendored
+// directory must be inside the vendor directory of a module, e.g.
+// project/vendor/github.com/me/dep, and only the packages of the dependency
+// that are vendored are compared. If the upstream copy is a module, it must
+// be the module of the vendored packages.
+func DiffVendored(vendorDir, upstreamDir string) (APIChanges, error) {
+	abs, err := filepath.Abs(vendorDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get absolute path of vendored dependency: %s", err)
+	}
+
+	sep := string(filepath.Separator) + "vendor" + string(filepath.Separator)
+	idx := strings.LastIndex(abs, sep)
+	if idx < 0 {
+		return nil, fmt.Errorf("%s is not inside a vendor directory", vendorDir)
+	}
+
+	root, importPath := abs[:idx], filepath.ToSlash(abs[idx+len(sep):])
+
+	// Make sure the upstream copy is the vendored dependency, or every
+	// vendored package would be reported as removed. Upstream copies
+	// without a module can't be checked.
+	module, err := ModulePath(upstreamDir)
+	switch {
+	case err == ErrNoModule:
+	case err != nil:
+		return nil, fmt.Errorf("unable to get module of %s: %s", upstreamDir, err)
+	case importPath != module && !strings.HasPrefix(importPath, module+"/"):
+		return nil, fmt.Errorf("%s is not part of module %s at %s", importPath, module, upstreamDir)
+	}
+
+	opts := LoadOptions{BuildFlags: []string{"-mod=vendor"}}
+	pkgs, err := loadPackages(root, []string{importPath + "/..."}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load vendored packages: %s", err)
+	}
+
+	prev, err := apiFromPackages(root, pkgs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := ProjectAPI(upstreamDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get API of %s: %s", upstreamDir, err)
+	}
+
+	var include = make([]string, len(prev))
+	for i, p := range prev {
+		include[i] = p.Path
+	}
+
+	return DiffFiltered(current, prev, include), nil
+}
@@ -0,0 +1,48 @@
+package semverlint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffVendored(t *testing.T) {
+	project := testModule(t, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.26\n\nrequire example.com/dep v1.0.0\n",
+		"m.go":   packageSource(`import _ "example.com/dep"`),
+		"vendor/modules.txt": "# example.com/dep v1.0.0\n" +
+			"## explicit; go 1.26\n" +
+			"example.com/dep\n",
+		"vendor/example.com/dep/dep.go": "package dep\n\nfunc F() {}\n\nfunc G() {}\n",
+	})
+
+	upstream := testModule(t, map[string]string{
+		"go.mod":     "module example.com/dep\n\ngo 1.26\n",
+		"dep.go":     "package dep\n\nfunc F(n int) {}\n\nfunc G() {}\n",
+		"sub/sub.go": "package sub\n\nfunc S() {}\n",
+	})
+
+	changes, err := DiffVendored(filepath.Join(project, "vendor", "example.com", "dep"), upstream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The package that is not vendored is not reported as added.
+	AssertChanges(t, changes, []string{
+		"example.com/dep: function F: argument n with type int at position 0: was added",
+	})
+
+	if _, err := DiffVendored(upstream, upstream); err == nil {
+		t.Error("expected an error for a directory outside of a vendor directory")
+	}
+
+	other := testModule(t, map[string]string{
+		"go.mod": "module example.com/other\n\ngo 1.26\n",
+		"dep.go": "package other\n\nfunc F() {}\n",
+	})
+
+	_, err = DiffVendored(filepath.Join(project, "vendor", "example.com", "dep"), other)
+	if want := "example.com/dep is not part of module example.com/other"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %v", want, err)
+	}
+}